@@ -1,7 +1,18 @@
 package container
 
-import "context"
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
+// Lifetime identifies the LifetimeStrategy deciding how the concretes of a binding are reused.
 type Lifetime string
 
 const (
@@ -18,19 +29,134 @@ const (
 type binding struct {
 	resolver interface{} // resolver is the function that is responsible for making the concrete.
 	concrete interface{} // concrete is the stored instance for singleton / scoped bindings.
-	lifetime Lifetime
+	// constructed is true once the binding holds its concrete, which may be nil.
+	constructed bool
+	lifetime    Lifetime
+	// structType is the struct allocated and filled by bindings registered with RegisterStruct.
+	structType reflect.Type
+	// injectMethods are the methods called on constructed concretes, registered with RegisterOptions.
+	injectMethods []string
+	// pool holds the idle concretes of bindings with a pooled lifetime.
+	pool *pool
+	// poolSize is the number of idle concretes kept by the pool, 0 for the size of the lifetime.
+	poolSize int
+
+	failurePolicy FailurePolicy
+	failure       *failure // failure is the failed resolution remembered according to the failure policy.
+
+	eager     bool   // eager bindings are constructed by WarmUp.
+	scopeKind string // scopeKind restricts a scoped binding to the scopes of the kind.
+
+	mu               sync.Mutex
+	build            sync.Mutex    // build serializes the constructions of the bindings caching their concrete.
+	builder          atomic.Int64  // builder is the goroutine holding build, 0 when no concrete is being constructed.
+	constructions    int           // constructions counts the calls made to the resolver.
+	constructionTime time.Duration // constructionTime is the total time spent in the resolver.
+}
+
+// clone returns a binding with the registration of b and no concrete.
+func (b *binding) clone() *binding {
+	return &binding{
+		resolver:      b.resolver,
+		lifetime:      b.lifetime,
+		structType:    b.structType,
+		injectMethods: b.injectMethods,
+		failurePolicy: b.failurePolicy,
+		eager:         b.eager,
+		scopeKind:     b.scopeKind,
+		poolSize:      b.poolSize,
+	}
 }
 
 // make resolves the binding if needed and returns the resolved concrete.
-func (b *binding) make(ctx context.Context, c *Container) (interface{}, error) {
-	if b.concrete != nil {
-		return b.concrete, nil
+// The second return value reports whether the concrete was returned from the cache instead of being constructed.
+// Instances are returned as is, resolvers are called according to the strategy of the lifetime.
+func (b *binding) make(ctx context.Context, c *Container, t reflect.Type, name string) (interface{}, bool, error) {
+	if b.resolver == nil {
+		concrete, _ := b.cached()
+		return concrete, true, nil
+	}
+
+	strategy := c.lifetimeStrategy(b.lifetime)
+	if strategy == nil {
+		return nil, false, fmt.Errorf("%w, no strategy registered for the lifetime '%s'", ErrInvalidLifetime, b.lifetime)
 	}
 
-	retVal, err := c.invoke(ctx, b.resolver)
-	if b.lifetime != Transient && err == nil {
-		b.concrete = retVal
+	if resolving(ctx, b) {
+		return nil, false, fmt.Errorf("%w, '%s' depends on itself", ErrCircularDependency, t.String())
+	}
+	frame := &resolvingFrame{binding: b, t: t, parent: resolvingFrames(ctx), ctx: resolverContext(ctx)}
+	ctx = context.WithValue(ctx, resolvingKey{}, frame)
+
+	return strategy.Resolve(ctx, LifetimeRequest{Type: t, Name: name, Lifetime: b.lifetime, Scope: c, binding: b})
+}
+
+type resolvingKey struct{}
+
+// resolvingFrame is a binding being resolved, linked to the binding whose resolution caused it.
+type resolvingFrame struct {
+	binding *binding
+	t       reflect.Type
+	parent  *resolvingFrame
+	ctx     context.Context // ctx is the context the resolution started with, before any frame was added to it.
+}
+
+func resolvingFrames(ctx context.Context) *resolvingFrame {
+	frame, _ := ctx.Value(resolvingKey{}).(*resolvingFrame)
+	return frame
+}
+
+// resolverContext returns the context handed to resolvers, injection methods and Init, which is the context the
+// resolution started with. The frames stay out of it, resolvers keeping their context resolve again from scratch.
+func resolverContext(ctx context.Context) context.Context {
+	if frame := resolvingFrames(ctx); frame != nil {
+		return frame.ctx
 	}
 
-	return retVal, err
+	return ctx
+}
+
+// goroutineID returns the id of the calling goroutine, read from the header of its stack trace, or 0 if it cannot be read.
+func goroutineID() int64 {
+	var buf [64]byte
+	fields := bytes.Fields(buf[:runtime.Stack(buf[:], false)])
+	if len(fields) < 2 {
+		return 0
+	}
+
+	id, _ := strconv.ParseInt(string(fields[1]), 10, 64)
+	return id
+}
+
+// resolving reports whether the binding is being resolved higher in the context, which means it depends on itself.
+func resolving(ctx context.Context, b *binding) bool {
+	for frame := resolvingFrames(ctx); frame != nil; frame = frame.parent {
+		if frame.binding == b {
+			return true
+		}
+	}
+
+	return false
+}
+
+// initialize calls Init on the concrete if it implements Initializer.
+func initialize(ctx context.Context, concrete interface{}) error {
+	initializer, ok := concrete.(Initializer)
+	if !ok || isNil(concrete) {
+		return nil
+	}
+
+	if err := initializer.Init(ctx); err != nil {
+		return fmt.Errorf("%w for type '%T', Error: %w", ErrInitializationFailed, concrete, err)
+	}
+
+	return nil
+}
+
+// cached returns the stored concrete and whether the binding holds one.
+func (b *binding) cached() (interface{}, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.concrete, b.constructed
 }
@@ -0,0 +1,142 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Argument supplies the value of a parameter of the function passed to CallWithResults or Invoke, the remaining
+// parameters being resolved from the container.
+type Argument struct {
+	position int          // position is the index of the parameter, when the argument matches by position.
+	byType   bool         // byType is true when the argument matches the parameters of type t instead of a position.
+	t        reflect.Type // t is the type of the parameters the argument matches, when it matches by type.
+	value    reflect.Value
+}
+
+// ArgAt supplies the value of the parameter at the position, starting at 0.
+// A nil value supplies the zero value of the parameter type.
+func ArgAt(position int, value interface{}) Argument {
+	return Argument{position: position, value: reflect.ValueOf(value)}
+}
+
+// ArgOf supplies the value of every parameter of type T.
+// Arguments supplied by position take precedence over arguments supplied by type.
+func ArgOf[T any](value T) Argument {
+	return Argument{
+		byType: true,
+		t:      reflect.TypeOf((*T)(nil)).Elem(),
+		value:  reflect.ValueOf(&value).Elem(),
+	}
+}
+
+// supply returns the values of the parameters of the function type supplied by the arguments, keyed by position.
+func supply(functionType reflect.Type, arguments []Argument) (map[int]reflect.Value, error) {
+	supplied := map[int]reflect.Value{}
+
+	for _, argument := range arguments {
+		if argument.byType {
+			continue
+		}
+
+		if argument.position < 0 || argument.position >= functionType.NumIn() {
+			return nil, fmt.Errorf("%w, argument at position %d is out of range, the function has %d parameters",
+				ErrInvalidReceiver, argument.position, functionType.NumIn())
+		}
+
+		parameterType := functionType.In(argument.position)
+		value := argument.value
+		if !value.IsValid() {
+			value = reflect.Zero(parameterType)
+		}
+
+		if !value.Type().AssignableTo(parameterType) {
+			return nil, fmt.Errorf("%w, argument of type '%s' at position %d is not assignable to '%s'",
+				ErrInvalidReceiver, value.Type().String(), argument.position, parameterType.String())
+		}
+
+		supplied[argument.position] = value
+	}
+
+	for _, argument := range arguments {
+		if !argument.byType {
+			continue
+		}
+
+		matched := false
+		for i := 0; i < functionType.NumIn(); i++ {
+			if functionType.In(i) != argument.t {
+				continue
+			}
+
+			matched = true
+			if _, exist := supplied[i]; !exist {
+				supplied[i] = argument.value
+			}
+		}
+
+		if !matched {
+			return nil, fmt.Errorf("%w, the function has no parameter of type '%s'", ErrInvalidReceiver, argument.t.String())
+		}
+	}
+
+	return supplied, nil
+}
+
+// CallWithResults invokes the function like Call does and returns its results.
+// The arguments supply some of the parameters explicitly, the others being resolved from the container.
+// When the last result of the function is an error, it is returned as the error instead of being part of the results.
+func (c *Container) CallWithResults(ctx context.Context, function interface{}, arguments ...Argument) ([]interface{}, error) {
+	if ctx == nil {
+		return nil, ErrContextRequired
+	}
+
+	functionType := reflect.TypeOf(function)
+	if functionType == nil || functionType.Kind() != reflect.Func {
+		return nil, ErrInvalidReceiver
+	}
+
+	values, err := c.arguments(ctx, function, arguments...)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := reflect.ValueOf(function).Call(values)
+
+	if count := functionType.NumOut(); count > 0 && functionType.Out(count-1) == errorType {
+		last := outputs[count-1]
+		outputs = outputs[:count-1]
+
+		if !last.IsNil() {
+			err = last.Interface().(error)
+		}
+	}
+
+	results := make([]interface{}, len(outputs))
+	for i, output := range outputs {
+		results[i] = output.Interface()
+	}
+
+	return results, err
+}
+
+// Invoke invokes the function with CallWithResults and returns its first result as R.
+// The function must return a value assignable to R, optionally followed by other results and an error.
+func Invoke[R any](ctx context.Context, c *Container, function interface{}, arguments ...Argument) (R, error) {
+	var result R
+
+	resultType := reflect.TypeOf((*R)(nil)).Elem()
+	functionType := reflect.TypeOf(function)
+	if functionType == nil || functionType.Kind() != reflect.Func ||
+		functionType.NumOut() == 0 || !functionType.Out(0).AssignableTo(resultType) {
+		return result, fmt.Errorf("%w, the function must return a value assignable to '%s'", ErrInvalidReceiver, resultType.String())
+	}
+
+	results, err := c.CallWithResults(ctx, function, arguments...)
+	if len(results) > 0 && results[0] != nil {
+		reflect.ValueOf(&result).Elem().Set(reflect.ValueOf(results[0]))
+	}
+
+	return result, err
+}
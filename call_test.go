@@ -0,0 +1,234 @@
+package container_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+func TestContainer_CallWithResults(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 1}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("square", func() Shape {
+		return &Square{a: 2}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("big", func() Shape {
+		return &Circle{a: 3}
+	})
+	assert.NoError(t, err)
+
+	results, err := c.CallWithResults(context.Background(), func(s Shape) (int, string) {
+		return s.GetArea(), "circle"
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1, "circle"}, results)
+}
+
+func TestContainer_CallWithResults_With_Error_Result(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 1}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("square", func() Shape {
+		return &Square{a: 2}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("big", func() Shape {
+		return &Circle{a: 3}
+	})
+	assert.NoError(t, err)
+
+	expected := errors.New("failed")
+
+	results, err := c.CallWithResults(context.Background(), func(s Shape) (int, error) {
+		return s.GetArea(), expected
+	})
+	assert.Same(t, expected, err)
+	assert.Equal(t, []interface{}{1}, results)
+
+	results, err = c.CallWithResults(context.Background(), func() error {
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestContainer_CallWithResults_With_Arguments(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 1}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("square", func() Shape {
+		return &Square{a: 2}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("big", func() Shape {
+		return &Circle{a: 3}
+	})
+	assert.NoError(t, err)
+
+	square := &Square{a: 9}
+
+	results, err := c.CallWithResults(context.Background(), func(id int, s Shape, db Database, name string) string {
+		assert.Equal(t, 42, id)
+		assert.Same(t, square, s)
+		assert.Nil(t, db)
+		return name
+	},
+		container.ArgAt(0, 42),
+		container.ArgAt(2, nil),
+		container.ArgAt(3, "explicit"),
+		container.ArgOf[Shape](square),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"explicit"}, results)
+}
+
+func TestContainer_CallWithResults_Position_Takes_Precedence_Over_Type(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 1}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("square", func() Shape {
+		return &Square{a: 2}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("big", func() Shape {
+		return &Circle{a: 3}
+	})
+	assert.NoError(t, err)
+
+	first := &Square{a: 1}
+	second := &Square{a: 2}
+
+	results, err := c.CallWithResults(context.Background(), func(a Shape, b Shape) int {
+		return a.GetArea()*10 + b.GetArea()
+	}, container.ArgOf[Shape](first), container.ArgAt(1, second))
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{12}, results)
+}
+
+func TestContainer_CallWithResults_With_Invalid_Arguments_It_Should_Fail(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 1}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("square", func() Shape {
+		return &Square{a: 2}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("big", func() Shape {
+		return &Circle{a: 3}
+	})
+	assert.NoError(t, err)
+
+	receiver := func(s Shape) {}
+
+	tests := map[string]container.Argument{
+		"out of range":   container.ArgAt(1, &Circle{}),
+		"negative":       container.ArgAt(-1, &Circle{}),
+		"not assignable": container.ArgAt(0, 42),
+		"unmatched type": container.ArgOf[Database](&MySQL{}),
+	}
+
+	for name, argument := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := c.CallWithResults(context.Background(), receiver, argument)
+			assert.ErrorIs(t, err, container.ErrInvalidReceiver)
+		})
+	}
+
+	_, err = c.CallWithResults(context.Background(), "not a function")
+	assert.ErrorIs(t, err, container.ErrInvalidReceiver)
+
+	_, err = c.CallWithResults(nil, receiver)
+	assert.ErrorIs(t, err, container.ErrContextRequired)
+}
+
+func TestInvoke(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 1}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("square", func() Shape {
+		return &Square{a: 2}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("big", func() Shape {
+		return &Circle{a: 3}
+	})
+	assert.NoError(t, err)
+
+	area, err := container.Invoke[int](context.Background(), c, func(s Shape) int {
+		return s.GetArea()
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, area)
+
+	shape, err := container.Invoke[Shape](context.Background(), c, func(factor int) (*Square, error) {
+		return &Square{a: factor}, nil
+	}, container.ArgOf(3))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, shape.GetArea())
+
+	shape, err = container.Invoke[Shape](context.Background(), c, func() (Shape, error) {
+		return nil, nil
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, shape)
+}
+
+func TestInvoke_With_Invalid_Function_It_Should_Fail(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 1}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("square", func() Shape {
+		return &Square{a: 2}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("big", func() Shape {
+		return &Circle{a: 3}
+	})
+	assert.NoError(t, err)
+
+	_, err = container.Invoke[int](context.Background(), c, func() string { return "" })
+	assert.ErrorIs(t, err, container.ErrInvalidReceiver)
+
+	_, err = container.Invoke[int](context.Background(), c, func() {})
+	assert.ErrorIs(t, err, container.ErrInvalidReceiver)
+}
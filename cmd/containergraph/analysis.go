@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/wbreza/container/v4"
+)
+
+// resolveNodes maps each reference to a node id.
+// A reference is either a node id, the label of a node or a type that is bound exactly once.
+func resolveNodes(graph *container.Graph, references []string) ([]string, error) {
+	ids := make([]string, 0, len(references))
+
+	for _, reference := range references {
+		matches := []string{}
+		for _, node := range graph.Nodes {
+			if node.ID == reference {
+				matches = []string{node.ID}
+				break
+			}
+
+			if node.Type == reference || (node.Name != "" && label(node) == reference) {
+				matches = append(matches, node.ID)
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("no node matches '%s'", reference)
+		case 1:
+			ids = append(ids, matches[0])
+		default:
+			return nil, fmt.Errorf("'%s' is ambiguous, use one of: %s", reference, strings.Join(matches, ", "))
+		}
+	}
+
+	return ids, nil
+}
+
+// label returns the name of a node displayed to users, its type followed by `#name` for named bindings.
+// Unlike the node ids, labels use package names instead of import paths.
+func label(node container.GraphNode) string {
+	if node.Name == "" {
+		return node.Type
+	}
+
+	return node.Type + "#" + node.Name
+}
+
+// labels returns the labels of the nodes of the graphs by id.
+func labels(graphs ...*container.Graph) map[string]string {
+	labels := map[string]string{}
+	for _, graph := range graphs {
+		for _, node := range graph.Nodes {
+			labels[node.ID] = label(node)
+		}
+	}
+
+	return labels
+}
+
+// dependencies returns the targets of the outgoing edges of every node.
+func dependencies(graph *container.Graph) map[string][]string {
+	edges := map[string][]string{}
+	for _, edge := range graph.Edges {
+		edges[edge.From] = append(edges[edge.From], edge.To)
+	}
+
+	return edges
+}
+
+// shortestPath returns the shortest chain of dependencies leading from one node to another, or nil if there is none.
+func shortestPath(graph *container.Graph, from string, to string) []string {
+	edges := dependencies(graph)
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == to {
+			path := []string{}
+			for id := to; id != ""; id = previous[id] {
+				path = append([]string{id}, path...)
+			}
+			return path
+		}
+
+		for _, next := range edges[current] {
+			if _, seen := previous[next]; !seen {
+				previous[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return nil
+}
+
+// unreachable returns the bindings that cannot be reached from any of the roots, sorted by id.
+func unreachable(graph *container.Graph, roots []string) []string {
+	edges := dependencies(graph)
+	reached := map[string]bool{}
+	queue := append([]string{}, roots...)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if reached[current] {
+			continue
+		}
+
+		reached[current] = true
+		queue = append(queue, edges[current]...)
+	}
+
+	ids := []string{}
+	for _, node := range graph.Nodes {
+		if !node.Missing && !reached[node.ID] {
+			ids = append(ids, node.ID)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids
+}
+
+// writeTree writes the dependencies of the roots as an indented tree.
+// Without roots, every node that no other node depends on is used as a root.
+// Dependencies already printed on the current branch are marked as cycles rather than expanded again.
+func writeTree(w io.Writer, graph *container.Graph, roots []string) error {
+	nodes := map[string]container.GraphNode{}
+	for _, node := range graph.Nodes {
+		nodes[node.ID] = node
+	}
+
+	edges := dependencies(graph)
+
+	if len(roots) == 0 {
+		dependedOn := map[string]bool{}
+		for _, edge := range graph.Edges {
+			dependedOn[edge.To] = true
+		}
+
+		for _, node := range graph.Nodes {
+			if !dependedOn[node.ID] {
+				roots = append(roots, node.ID)
+			}
+		}
+	}
+
+	var sb strings.Builder
+	var walk func(id string, prefix string, branch map[string]bool)
+
+	walk = func(id string, prefix string, branch map[string]bool) {
+		children := edges[id]
+		for i, child := range children {
+			connector, indent := "├── ", "│   "
+			if i == len(children)-1 {
+				connector, indent = "└── ", "    "
+			}
+
+			if branch[child] {
+				fmt.Fprintf(&sb, "%s%s%s (cycle)\n", prefix, connector, describe(nodes[child]))
+				continue
+			}
+
+			fmt.Fprintf(&sb, "%s%s%s\n", prefix, connector, describe(nodes[child]))
+
+			branch[child] = true
+			walk(child, prefix+indent, branch)
+			delete(branch, child)
+		}
+	}
+
+	for _, root := range roots {
+		fmt.Fprintln(&sb, describe(nodes[root]))
+		walk(root, "", map[string]bool{root: true})
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// describe returns a one line summary of a node.
+func describe(node container.GraphNode) string {
+	if node.Missing {
+		return label(node) + " [missing]"
+	}
+
+	details := []string{string(node.Lifetime)}
+	if node.Instance {
+		details = append(details, "instance")
+	}
+	if node.Scope > 0 {
+		details = append(details, fmt.Sprintf("scope %d", node.Scope))
+	}
+
+	return fmt.Sprintf("%s [%s]", label(node), strings.Join(details, ", "))
+}
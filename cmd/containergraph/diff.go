@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/wbreza/container/v4"
+)
+
+// diff describes how the wiring changed between two graphs.
+// Lines start with `+` for additions, `-` for removals and `~` for bindings whose registration changed.
+func diff(before *container.Graph, after *container.Graph) []string {
+	lines := []string{}
+	names := labels(before, after)
+
+	beforeNodes := map[string]container.GraphNode{}
+	for _, node := range before.Nodes {
+		beforeNodes[node.ID] = node
+	}
+
+	afterNodes := map[string]container.GraphNode{}
+	for _, node := range after.Nodes {
+		afterNodes[node.ID] = node
+	}
+
+	for _, node := range after.Nodes {
+		previous, exist := beforeNodes[node.ID]
+		if !exist {
+			lines = append(lines, "+ node "+describe(node))
+		} else if previous != node {
+			lines = append(lines, fmt.Sprintf("~ node %s -> %s", describe(previous), describe(node)))
+		}
+	}
+
+	for _, node := range before.Nodes {
+		if _, exist := afterNodes[node.ID]; !exist {
+			lines = append(lines, "- node "+describe(node))
+		}
+	}
+
+	beforeEdges := map[container.GraphEdge]bool{}
+	for _, edge := range before.Edges {
+		beforeEdges[edge] = true
+	}
+
+	afterEdges := map[container.GraphEdge]bool{}
+	for _, edge := range after.Edges {
+		afterEdges[edge] = true
+	}
+
+	for _, edge := range after.Edges {
+		if !beforeEdges[edge] {
+			lines = append(lines, "+ edge "+describeEdge(edge, names))
+		}
+	}
+
+	for _, edge := range before.Edges {
+		if !afterEdges[edge] {
+			lines = append(lines, "- edge "+describeEdge(edge, names))
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i][2:] < lines[j][2:]
+	})
+
+	return lines
+}
+
+// describeEdge returns a one line summary of an edge.
+func describeEdge(edge container.GraphEdge, names map[string]string) string {
+	s := fmt.Sprintf("%s -> %s", names[edge.From], names[edge.To])
+	if edge.Field != "" {
+		s += fmt.Sprintf(" (field %s)", edge.Field)
+	}
+	if edge.Unresolved {
+		s += " [unresolved]"
+	}
+
+	return s
+}
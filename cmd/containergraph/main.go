@@ -0,0 +1,201 @@
+// Command containergraph renders, queries and diffs dependency graphs exported with container.Graph.WriteJSON.
+//
+// Usage:
+//
+//	containergraph render [-format tree|dot|mermaid|json] [-root id]... graph.json
+//	containergraph path graph.json from to
+//	containergraph unreachable -root id [-root id]... graph.json
+//	containergraph diff old.json new.json
+//
+// Nodes are referenced by their id or by their type when the type is bound only once.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/wbreza/container/v4"
+)
+
+var errUsage = errors.New("usage: containergraph render|path|unreachable|diff [flags] files...")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command described by args and writes its output to stdout.
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "render":
+		return runRender(args[1:], stdout)
+	case "path":
+		return runPath(args[1:], stdout)
+	case "unreachable":
+		return runUnreachable(args[1:], stdout)
+	case "diff":
+		return runDiff(args[1:], stdout)
+	default:
+		return fmt.Errorf("unknown command '%s'\n%w", args[0], errUsage)
+	}
+}
+
+func runRender(args []string, stdout io.Writer) error {
+	var roots rootFlags
+
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	format := flags.String("format", "tree", "output format: tree, dot, mermaid or json")
+	flags.Var(&roots, "root", "node to start the tree from, may be repeated (tree format only)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("render expects a single graph file\n%w", errUsage)
+	}
+
+	graph, err := readGraph(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "tree":
+		ids, err := resolveNodes(graph, roots)
+		if err != nil {
+			return err
+		}
+		return writeTree(stdout, graph, ids)
+	case "dot":
+		return graph.WriteDOT(stdout)
+	case "mermaid":
+		return graph.WriteMermaid(stdout)
+	case "json":
+		return graph.WriteJSON(stdout)
+	default:
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+}
+
+func runPath(args []string, stdout io.Writer) error {
+	if len(args) != 3 {
+		return fmt.Errorf("path expects a graph file, a source and a target\n%w", errUsage)
+	}
+
+	graph, err := readGraph(args[0])
+	if err != nil {
+		return err
+	}
+
+	ids, err := resolveNodes(graph, args[1:])
+	if err != nil {
+		return err
+	}
+
+	path := shortestPath(graph, ids[0], ids[1])
+	if path == nil {
+		return fmt.Errorf("no dependency path from '%s' to '%s'", ids[0], ids[1])
+	}
+
+	names := labels(graph)
+	for i, id := range path {
+		path[i] = names[id]
+	}
+
+	_, err = fmt.Fprintln(stdout, strings.Join(path, " -> "))
+	return err
+}
+
+func runUnreachable(args []string, stdout io.Writer) error {
+	var roots rootFlags
+
+	flags := flag.NewFlagSet("unreachable", flag.ContinueOnError)
+	flags.Var(&roots, "root", "entry point of the application, may be repeated")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 || len(roots) == 0 {
+		return fmt.Errorf("unreachable expects at least one -root and a single graph file\n%w", errUsage)
+	}
+
+	graph, err := readGraph(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	ids, err := resolveNodes(graph, roots)
+	if err != nil {
+		return err
+	}
+
+	names := labels(graph)
+	for _, id := range unreachable(graph, ids) {
+		if _, err := fmt.Fprintln(stdout, names[id]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runDiff(args []string, stdout io.Writer) error {
+	if len(args) != 2 {
+		return fmt.Errorf("diff expects two graph files\n%w", errUsage)
+	}
+
+	before, err := readGraph(args[0])
+	if err != nil {
+		return err
+	}
+
+	after, err := readGraph(args[1])
+	if err != nil {
+		return err
+	}
+
+	for _, line := range diff(before, after) {
+		if _, err := fmt.Fprintln(stdout, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readGraph(path string) (*container.Graph, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	graph, err := container.ReadGraph(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return graph, nil
+}
+
+// rootFlags collects the values of a repeated flag.
+type rootFlags []string
+
+func (r *rootFlags) String() string {
+	return strings.Join(*r, ",")
+}
+
+func (r *rootFlags) Set(value string) error {
+	*r = append(*r, value)
+	return nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+type Config struct{}
+
+type Database struct{}
+
+type Cache struct{}
+
+type Server struct{}
+
+func writeGraph(t *testing.T, c *container.Container) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "graph.json")
+
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	assert.NoError(t, c.Graph().WriteJSON(file))
+	return path
+}
+
+func TestRun_Render_Tree(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+	assert.NoError(t, c.RegisterTransient(func(db *Database) *Server { return &Server{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Cache { return &Cache{} }))
+
+	path := writeGraph(t, c)
+
+	var out bytes.Buffer
+	err := run([]string{"render", "-root", "*main.Server", path}, &out)
+	assert.NoError(t, err)
+
+	expected := `*main.Server [transient]
+└── *main.Database [singleton]
+    └── *main.Config [singleton]
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestRun_Render_Tree_Without_Roots(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+	assert.NoError(t, c.RegisterTransient(func(db *Database) *Server { return &Server{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Cache { return &Cache{} }))
+
+	path := writeGraph(t, c)
+
+	var out bytes.Buffer
+	err := run([]string{"render", path}, &out)
+	assert.NoError(t, err)
+
+	expected := `*main.Cache [singleton]
+└── *main.Config [singleton]
+*main.Server [transient]
+└── *main.Database [singleton]
+    └── *main.Config [singleton]
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestRun_Render_DOT(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+	assert.NoError(t, c.RegisterTransient(func(db *Database) *Server { return &Server{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Cache { return &Cache{} }))
+
+	path := writeGraph(t, c)
+
+	var expected bytes.Buffer
+	assert.NoError(t, c.Graph().WriteDOT(&expected))
+
+	var out bytes.Buffer
+	err := run([]string{"render", "-format", "dot", path}, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, expected.String(), out.String())
+}
+
+func TestRun_Render_Unknown_Format_It_Should_Fail(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+	assert.NoError(t, c.RegisterTransient(func(db *Database) *Server { return &Server{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Cache { return &Cache{} }))
+
+	path := writeGraph(t, c)
+
+	err := run([]string{"render", "-format", "svg", path}, &bytes.Buffer{})
+	assert.Error(t, err)
+}
+
+func TestRun_Path(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+	assert.NoError(t, c.RegisterTransient(func(db *Database) *Server { return &Server{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Cache { return &Cache{} }))
+
+	path := writeGraph(t, c)
+
+	var out bytes.Buffer
+	err := run([]string{"path", path, "*main.Server", "*main.Config"}, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, "*main.Server -> *main.Database -> *main.Config\n", out.String())
+
+	err = run([]string{"path", path, "*main.Config", "*main.Server"}, &out)
+	assert.Error(t, err)
+}
+
+func TestRun_Unreachable(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+	assert.NoError(t, c.RegisterTransient(func(db *Database) *Server { return &Server{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Cache { return &Cache{} }))
+
+	path := writeGraph(t, c)
+
+	var out bytes.Buffer
+	err := run([]string{"unreachable", "-root", "*main.Server", path}, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, "*main.Cache\n", out.String())
+}
+
+func TestRun_Unreachable_Without_Root_It_Should_Fail(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+	assert.NoError(t, c.RegisterTransient(func(db *Database) *Server { return &Server{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Cache { return &Cache{} }))
+
+	path := writeGraph(t, c)
+
+	err := run([]string{"unreachable", path}, &bytes.Buffer{})
+	assert.ErrorIs(t, err, errUsage)
+}
+
+func TestRun_Diff(t *testing.T) {
+	previous := container.New()
+
+	assert.NoError(t, previous.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, previous.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+	assert.NoError(t, previous.RegisterTransient(func(db *Database) *Server { return &Server{} }))
+	assert.NoError(t, previous.RegisterSingleton(func(config *Config) *Cache { return &Cache{} }))
+
+	before := writeGraph(t, previous)
+
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+	assert.NoError(t, c.RegisterTransient(func(db *Database) *Server { return &Server{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Cache { return &Cache{} }))
+	assert.NoError(t, c.RegisterSingleton(func(db *Database) *Server { return &Server{} }))
+	assert.NoError(t, c.RegisterNamedSingleton("replica", func(config *Config) *Database { return &Database{} }))
+	after := writeGraph(t, c)
+
+	var out bytes.Buffer
+	err := run([]string{"diff", before, after}, &out)
+	assert.NoError(t, err)
+
+	expected := `+ edge *main.Database#replica -> *main.Config
++ node *main.Database#replica [singleton]
+~ node *main.Server [transient] -> *main.Server [singleton]
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestRun_Unknown_Command_It_Should_Fail(t *testing.T) {
+	err := run([]string{"explode"}, &bytes.Buffer{})
+	assert.ErrorIs(t, err, errUsage)
+
+	err = run([]string{}, &bytes.Buffer{})
+	assert.ErrorIs(t, err, errUsage)
+}
@@ -6,8 +6,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
-	"unsafe"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var (
@@ -16,48 +19,169 @@ var (
 	ErrInvalidAbstraction = errors.New("invalid abstraction")
 	ErrInvalidReceiver    = errors.New("invalid receiver")
 	ErrInvalidStructure   = errors.New("invalid structure")
+	ErrInvalidLifetime    = errors.New("invalid lifetime")
 
 	// Errors encountered while resolving, calling or filling
-	ErrContextRequired  = errors.New("context is required. If you don't have a context pass 'context.Background()' or 'context.TODO()'")
-	ErrResolutionFailed = errors.New("failed making instance")
-	ErrBindingNotFound  = errors.New("no binding found")
+	ErrContextRequired      = errors.New("context is required. If you don't have a context pass 'context.Background()' or 'context.TODO()'")
+	ErrResolutionFailed     = errors.New("failed making instance")
+	ErrBindingNotFound      = errors.New("no binding found")
+	ErrInitializationFailed = errors.New("initialization failed")
+	ErrCircularDependency   = errors.New("circular dependency")
+	ErrScopeMismatch        = errors.New("scope mismatch")
 )
 
+// Initializer is implemented by concretes needing initialization once constructed.
+// The container calls Init on every concrete its resolvers construct, after the fields of structs registered with
+// RegisterStruct are filled and the injection methods are called, and fails the resolution when it returns an error.
+// Init is not called again when a cached concrete is returned, nor on instances registered with RegisterInstance.
+type Initializer interface {
+	Init(ctx context.Context) error
+}
+
 // Container holds the bindings and provides methods to interact with them.
 // It is the entry point in the package.
 type Container struct {
-	parent   *Container
-	bindings map[reflect.Type]map[string]*binding
+	parent      *Container
+	mu          sync.RWMutex
+	bindings    map[reflect.Type]map[string]*binding
+	disposables []io.Closer // disposables are the constructed concretes closed along with the container.
+	disposal    bool        // disposal closes the constructed concretes along with the container, see WithDisposal.
+
+	activeScopes atomic.Int64 // activeScopes counts the open scopes created from this container or its descendants.
+	closed       atomic.Bool
+
+	tracer    *Tracer
+	metrics   MetricsCollector
+	logger    Logger
+	logLevels LogLevels
+
+	injectPrefix     string // injectPrefix is the prefix of the methods called on constructed concretes, if any.
+	scopeFromContext bool   // scopeFromContext resolves scoped bindings from the scope carried by the context.
+
+	lifetimes map[Lifetime]LifetimeStrategy // lifetimes holds the strategies registered with RegisterLifetime.
+	leases    map[*lease]struct{}           // leases holds the pooled concretes resolved by the container.
+
+	warmUpParallelism int
+
+	kind string // kind is the kind of the scope, set with WithKind.
+	// scoped holds the bindings of the scope for the scoped bindings registered in its parents, by registered binding.
+	scoped map[*binding]*binding
+
+	leaks  *leakTracker // leaks tracks the open scopes, set with WithLeakDetection.
+	leakID uint64       // leakID identifies the container in the leak tracker.
+	// leakToken reports the scope to the leak tracker when it is garbage collected without being closed.
+	leakToken *leakToken
 }
 
+// Option configures a Container created with New.
+// Scopes created with NewScope inherit the options of their parent.
+type Option func(*Container)
+
 // New creates a new instance of the Container.
-func New() *Container {
-	return &Container{
-		bindings: make(map[reflect.Type]map[string]*binding),
+func New(options ...Option) *Container {
+	c := &Container{
+		bindings:  make(map[reflect.Type]map[string]*binding),
+		logLevels: DefaultLogLevels,
 	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
 }
 
 // NewScope creates a new child container scope.
-// Scoped bindings are copied from the parent container to the child container and act as singletons within the new scope
-func (c *Container) NewScope() (*Container, error) {
+// Scoped bindings registered in the parent containers, before or after the scope is created, act as singletons within
+// the new scope. The options configure the scope before it is returned; the scope is not created if one of them fails.
+func (c *Container) NewScope(options ...ScopeOption) (*Container, error) {
 	childContainer := New()
 	childContainer.parent = c
-
-	for _, outerBinding := range c.bindings {
-		for name, binding := range outerBinding {
-			if binding.lifetime == Scoped {
-				if err := childContainer.bind(binding.resolver, name, binding.lifetime); err != nil {
-					return nil, err
-				}
-			}
+	childContainer.tracer = c.tracer
+	childContainer.metrics = c.metrics
+	childContainer.disposal = c.disposal
+	childContainer.injectPrefix = c.injectPrefix
+	childContainer.scopeFromContext = c.scopeFromContext
+	childContainer.warmUpParallelism = c.warmUpParallelism
+	childContainer.logger = c.logger
+	childContainer.logLevels = c.logLevels
+
+	for _, option := range options {
+		if err := option(childContainer); err != nil {
+			return nil, err
 		}
 	}
 
+	for current := c; current != nil; current = current.parent {
+		current.activeScopes.Add(1)
+	}
+
+	if c.metrics != nil {
+		c.metrics.ScopeOpened()
+	}
+
+	c.trackScope(childContainer)
+	c.log(context.Background(), LogEvent{Kind: LogScope})
+
 	return childContainer, nil
 }
 
+// Close closes a scope created with NewScope so it is no longer counted as an active scope.
+// The concretes constructed by the container are closed along with it when it was created with WithDisposal.
+// The pooled concretes leased by the container are returned to their pools beforehand, see Pooled.
+// Closing a container more than once has no effect.
+func (c *Container) Close() error {
+	if !c.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	if c.parent != nil {
+		for current := c.parent; current != nil; current = current.parent {
+			current.activeScopes.Add(-1)
+		}
+
+		if c.metrics != nil {
+			c.metrics.ScopeClosed()
+		}
+
+		c.untrackScope()
+	}
+
+	var errs []error
+	if err := c.releaseLeases(); err != nil {
+		errs = append(errs, err)
+	}
+
+	c.mu.Lock()
+	disposables := c.disposables
+	c.disposables = nil
+	c.mu.Unlock()
+
+	for i := len(disposables) - 1; i >= 0; i-- {
+		err := disposables[i].Close()
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		if c.metrics != nil {
+			c.metrics.DisposableClosed()
+		}
+
+		c.log(context.Background(), LogEvent{Kind: LogDisposal, Type: reflect.TypeOf(disposables[i]), Err: err})
+	}
+
+	if err := c.disposeBindings(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
 // Reset deletes all the existing bindings and empties the container.
 func (c *Container) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	for k := range c.bindings {
 		delete(c.bindings, k)
 	}
@@ -67,6 +191,19 @@ type RegisterOptions struct {
 	Resolver interface{}
 	Name     string
 	Lifetime Lifetime
+	// InjectMethods are the names of the methods called on every concrete the resolver constructs, in order, with
+	// their parameters resolved like Call does. See WithMethodInjection.
+	InjectMethods []string
+	// FailurePolicy decides whether failed resolutions are retried or remembered.
+	FailurePolicy FailurePolicy
+	// Eager marks a singleton to be constructed by WarmUp instead of on its first resolution.
+	Eager bool
+	// ScopeKind restricts a scoped binding to the scopes of the kind, see WithKind. The binding is resolved once per
+	// nearest scope of the kind, including from the scopes nested in it, and fails to resolve outside of such a scope.
+	ScopeKind string
+	// PoolSize is the number of idle concretes kept by the pool of a pooled binding, in place of the size of its
+	// lifetime. Zero keeps the size of the lifetime.
+	PoolSize int
 }
 
 // Registers the resolver with the specified options.
@@ -75,7 +212,35 @@ func (c *Container) Register(options RegisterOptions) error {
 		options.Lifetime = Singleton
 	}
 
-	return c.bind(options.Resolver, options.Name, options.Lifetime)
+	if options.Eager && options.Lifetime != Singleton {
+		return fmt.Errorf("%w, only singletons can be eager", ErrInvalidLifetime)
+	}
+
+	if options.ScopeKind != "" && options.Lifetime != Scoped {
+		return fmt.Errorf("%w, only scoped bindings can be restricted to a scope kind", ErrInvalidLifetime)
+	}
+
+	if options.PoolSize < 0 {
+		return fmt.Errorf("%w, the pool size cannot be negative", ErrInvalidLifetime)
+	}
+
+	if _, pooled := c.lifetimeStrategy(options.Lifetime).(*pooledLifetime); options.PoolSize > 0 && !pooled {
+		return fmt.Errorf("%w, only pooled bindings can have a pool size", ErrInvalidLifetime)
+	}
+
+	abstraction, newBinding, err := c.newBinding(options.Resolver, options.Lifetime)
+	if err != nil {
+		return err
+	}
+
+	newBinding.injectMethods = options.InjectMethods
+	newBinding.failurePolicy = options.FailurePolicy
+	newBinding.eager = options.Eager
+	newBinding.scopeKind = options.ScopeKind
+	newBinding.poolSize = options.PoolSize
+	c.register(abstraction, options.Name, newBinding)
+
+	return nil
 }
 
 // Invokes the resolver and registers the instance with the specified options.
@@ -104,6 +269,10 @@ func (c *Container) RegisterInstance(instance interface{}) error {
 // RegisterNamedInstance binds an instance to the container in singleton mode with a name.
 func (c *Container) RegisterNamedInstance(name string, instance interface{}) error {
 	t := reflect.TypeOf(instance)
+	if t == nil {
+		return fmt.Errorf("%w, cannot register an untyped nil instance, use RegisterInstanceAs to register a nil of a given type", ErrInvalidAbstraction)
+	}
+
 	if t.Kind() == reflect.Func {
 		return fmt.Errorf("%w, cannot register a function as an instance", ErrInvalidResolver)
 	}
@@ -182,7 +351,7 @@ func (c *Container) Call(ctx context.Context, function interface{}) error {
 	if len(result) == 0 {
 		return nil
 	} else if len(result) == 1 && result[0].CanInterface() {
-		if result[0].IsNil() {
+		if result[0].Kind() == reflect.Interface && result[0].IsNil() {
 			return nil
 		}
 		if err, ok := result[0].Interface().(error); ok {
@@ -216,7 +385,7 @@ func (c *Container) ResolveNamed(ctx context.Context, name string, abstraction i
 	elem := receiverType.Elem()
 
 	if instance, err := c.make(ctx, elem, name); err == nil {
-		reflect.ValueOf(abstraction).Elem().Set(reflect.ValueOf(instance))
+		reflect.ValueOf(abstraction).Elem().Set(valueOf(elem, instance))
 		return nil
 	} else {
 		if name == "" {
@@ -227,7 +396,12 @@ func (c *Container) ResolveNamed(ctx context.Context, name string, abstraction i
 	}
 }
 
-// Fill takes a struct and resolves the fields with the tag `container:"inject"`
+// Fill takes a struct and resolves the fields with a `container` tag.
+// The tag holds comma separated options: `type` or `name=x` select the binding (`name` alone uses the field name),
+// `optional` leaves the field untouched when there is no binding, `group` fills a slice with every binding of the
+// element type, `lazy` fills a `func() T` or `func() (T, error)` field resolving on call, `fill` fills the fields of a
+// nested struct or pointer to struct, and `-` skips the field.
+// Embedded structs are filled recursively and nil pointers to structs with fields to inject are allocated.
 func (c *Container) Fill(ctx context.Context, structure interface{}) error {
 	if ctx == nil {
 		return ErrContextRequired
@@ -247,34 +421,7 @@ func (c *Container) Fill(ctx context.Context, structure interface{}) error {
 		return ErrInvalidStructure
 	}
 
-	s := reflect.ValueOf(structure).Elem()
-
-	for i := 0; i < s.NumField(); i++ {
-		f := s.Field(i)
-
-		if t, exist := s.Type().Field(i).Tag.Lookup("container"); exist {
-			var name string
-
-			if t == "type" {
-				name = ""
-			} else if t == "name" {
-				name = s.Type().Field(i).Name
-			} else {
-				return fmt.Errorf("%w, %v has an invalid struct tag", ErrInvalidStructure, s.Type().Field(i).Name)
-			}
-
-			if instance, err := c.make(ctx, f.Type(), name); err == nil {
-				ptr := reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
-				ptr.Set(reflect.ValueOf(instance))
-
-				continue
-			} else {
-				return fmt.Errorf("%w for field '%v', Error: %w", ErrResolutionFailed, s.Type().Field(i).Name, err)
-			}
-		}
-	}
-
-	return nil
+	return c.fill(ctx, reflect.ValueOf(structure).Elem())
 }
 
 // Validate checks the container for any errors and ensures all registered types can be resolved.
@@ -283,11 +430,32 @@ func (c *Container) Validate(ctx context.Context) error {
 		return ErrContextRequired
 	}
 
-	for t, binding := range c.bindings {
-		for name := range binding {
-			if _, err := c.make(ctx, t, name); err != nil {
+	for _, visible := range c.visibleBindings() {
+		if visible.scope != c {
+			continue
+		}
+
+		// Bindings restricted to a scope kind cannot be resolved outside of a scope of the kind.
+		if kind := visible.binding.scopeKind; kind != "" && c.scopeOfKind(kind) == nil {
+			continue
+		}
+
+		// Pooled concretes are returned to their pool right away instead of being leased to the container.
+		if _, pooled := c.lifetimeStrategy(visible.binding.lifetime).(*pooledLifetime); pooled {
+			_, leased, err := c.acquire(ctx, visible.t, visible.name, visible.binding)
+			if err != nil {
+				return err
+			}
+
+			if err := leased.release(); err != nil {
 				return err
 			}
+
+			continue
+		}
+
+		if _, err := c.make(ctx, visible.t, visible.name); err != nil {
+			return err
 		}
 	}
 
@@ -296,31 +464,61 @@ func (c *Container) Validate(ctx context.Context) error {
 
 // bind maps an abstraction to concrete and instantiates if it is a singleton binding.
 func (c *Container) bind(resolver interface{}, name string, lifetime Lifetime) error {
+	abstraction, newBinding, err := c.newBinding(resolver, lifetime)
+	if err != nil {
+		return err
+	}
+
+	c.register(abstraction, name, newBinding)
+
+	return nil
+}
+
+// newBinding creates the binding of an instance or a resolver function and returns it along with its abstraction.
+func (c *Container) newBinding(resolver interface{}, lifetime Lifetime) (reflect.Type, *binding, error) {
+	if c.lifetimeStrategy(lifetime) == nil {
+		return nil, nil, fmt.Errorf("%w, no strategy registered for the lifetime '%s'", ErrInvalidLifetime, lifetime)
+	}
+
 	reflectedResolver := reflect.TypeOf(resolver)
+	if reflectedResolver == nil {
+		return nil, nil, fmt.Errorf("%w, cannot register an untyped nil", ErrInvalidAbstraction)
+	}
+
+	// For instance based bindings
+	abstraction := reflectedResolver
+	newBinding := &binding{concrete: resolver, constructed: true, lifetime: lifetime}
 
 	// For function based bindings
 	if reflectedResolver.Kind() == reflect.Func {
-		if reflectedResolver.NumOut() > 0 {
-			if _, exist := c.bindings[reflectedResolver.Out(0)]; !exist {
-				c.bindings[reflectedResolver.Out(0)] = make(map[string]*binding)
-			}
-		}
-
 		if err := c.validateResolverFunction(reflectedResolver); err != nil {
-			return err
+			return nil, nil, err
 		}
 
-		c.bindings[reflectedResolver.Out(0)][name] = &binding{resolver: resolver, lifetime: lifetime}
+		abstraction = reflectedResolver.Out(0)
+		newBinding = &binding{resolver: resolver, lifetime: lifetime}
+	}
 
-	} else { // For instance based bindings
-		if _, exist := c.bindings[reflectedResolver]; !exist {
-			c.bindings[reflectedResolver] = make(map[string]*binding)
-		}
+	return abstraction, newBinding, nil
+}
 
-		c.bindings[reflectedResolver][name] = &binding{concrete: resolver, lifetime: lifetime}
+// register stores the binding for the abstraction and name, replacing any binding already registered with them.
+func (c *Container) register(abstraction reflect.Type, name string, newBinding *binding) {
+	c.mu.Lock()
+	if _, exist := c.bindings[abstraction]; !exist {
+		c.bindings[abstraction] = make(map[string]*binding)
 	}
 
-	return nil
+	_, overwrite := c.bindings[abstraction][name]
+	c.bindings[abstraction][name] = newBinding
+	c.mu.Unlock()
+
+	kind := LogRegistration
+	if overwrite {
+		kind = LogOverwrite
+	}
+
+	c.log(context.Background(), LogEvent{Kind: kind, Type: abstraction, Name: name, Lifetime: newBinding.lifetime})
 }
 
 func (c *Container) validateResolverFunction(funcType reflect.Type) error {
@@ -360,44 +558,166 @@ func (c *Container) invoke(ctx context.Context, function interface{}) (interface
 // make resolves the binding and returns the concrete.
 // Search up any parent container scopes if the binding is not found in current scope.
 func (c *Container) make(ctx context.Context, t reflect.Type, name string) (interface{}, error) {
-	current := c
-	var binding *binding
+	binding, owner := c.lookup(t, name)
+	if binding == nil {
+		err := fmt.Errorf("%w for abstraction '%s'", ErrBindingNotFound, t.String())
+		c.log(ctx, LogEvent{Kind: LogResolution, Type: t, Name: name, Err: err})
+
+		return nil, err
+	}
 
-	for {
-		if found, exist := current.bindings[t][name]; exist {
-			binding = found
-			break
+	if binding.lifetime == Scoped {
+		if scope := c.contextScope(ctx); scope != nil {
+			return scope.make(ctx, t, name)
 		}
+	}
 
-		if current.parent == nil {
-			break
-		} else {
-			current = current.parent
+	if binding.scopeKind != "" {
+		scope := c.scopeOfKind(binding.scopeKind)
+		if scope == nil {
+			err := fmt.Errorf("%w, '%s' can only be resolved within a scope of kind '%s'", ErrScopeMismatch, t.String(), binding.scopeKind)
+			c.log(ctx, LogEvent{Kind: LogResolution, Type: t, Name: name, Lifetime: binding.lifetime, Err: err})
+
+			return nil, err
+		}
+
+		if scope != c {
+			return scope.make(ctx, t, name)
 		}
 	}
 
-	if binding == nil {
-		return nil, fmt.Errorf("%w for abstraction '%s'", ErrBindingNotFound, t.String())
+	if binding.lifetime == Scoped && owner != c && binding.resolver != nil {
+		binding, owner = c.scopedBinding(binding), c
+	}
+
+	start := time.Now()
+
+	var frame *TraceFrame
+	if c.tracer != nil {
+		ctx, frame = c.tracer.begin(ctx, t, name, binding.lifetime)
 	}
 
-	return binding.make(ctx, c)
+	concrete, cached, err := binding.make(ctx, c, t, name)
+
+	if c.tracer != nil {
+		c.tracer.end(frame, cached, err)
+	}
+
+	if c.metrics != nil {
+		c.metrics.ResolutionCompleted(Resolution{
+			Type:     t,
+			Name:     name,
+			Lifetime: binding.lifetime,
+			Cached:   cached,
+			Duration: time.Since(start),
+			Err:      err,
+		})
+	}
+
+	if c.logger != nil {
+		kind := LogResolution
+		if cached {
+			kind = LogCacheHit
+		}
+
+		c.log(ctx, LogEvent{Kind: kind, Type: t, Name: name, Lifetime: binding.lifetime, Duration: time.Since(start), Err: err})
+	}
+
+	if _, disposing := c.lifetimeStrategy(binding.lifetime).(disposingLifetime); disposing {
+		return concrete, err
+	}
+
+	if closer, ok := concrete.(io.Closer); ok && c.disposal && !cached && err == nil && !isNil(concrete) {
+		if binding.lifetime == Transient {
+			// The root container lives as long as the application, the transient concretes it resolves are left to
+			// the caller instead of being held until it is closed.
+			if c.parent == nil {
+				return concrete, err
+			}
+
+			owner = c
+		}
+
+		owner.track(closer)
+	}
+
+	return concrete, err
+}
+
+// lookup finds the binding for the type and name along with the container that owns it.
+// Search up any parent container scopes if the binding is not found in current scope.
+func (c *Container) lookup(t reflect.Type, name string) (*binding, *Container) {
+	for current := c; current != nil; current = current.parent {
+		current.mu.RLock()
+		found, exist := current.bindings[t][name]
+		current.mu.RUnlock()
+
+		if exist {
+			return found, current
+		}
+	}
+
+	return nil, nil
+}
+
+var containerType = reflect.TypeOf((*Container)(nil))
+
+// valueOf returns the value of the concrete resolved for the type t, the zero value of t for a nil concrete.
+func valueOf(t reflect.Type, concrete interface{}) reflect.Value {
+	if concrete == nil {
+		return reflect.Zero(t)
+	}
+
+	return reflect.ValueOf(concrete)
+}
+
+// isNil reports whether the concrete is nil or a nil pointer, map, slice, function, channel or interface.
+func isNil(concrete interface{}) bool {
+	if concrete == nil {
+		return true
+	}
+
+	switch value := reflect.ValueOf(concrete); value.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return value.IsNil()
+	default:
+		return false
+	}
 }
 
 // arguments returns the list of resolved arguments for a function.
-func (c *Container) arguments(ctx context.Context, function interface{}) ([]reflect.Value, error) {
+// Context parameters receive the context of the resolution and *Container parameters receive the container performing
+// the resolution, which may be a scope of the container the resolver is registered in.
+// Parameters supplied by the overrides are not resolved.
+func (c *Container) arguments(ctx context.Context, function interface{}, overrides ...Argument) ([]reflect.Value, error) {
 	reflectedFunction := reflect.TypeOf(function)
 	argumentsCount := reflectedFunction.NumIn()
 	arguments := make([]reflect.Value, argumentsCount)
 	contextType := reflect.TypeOf((*context.Context)(nil)).Elem()
 
+	supplied, err := supply(reflectedFunction, overrides)
+	if err != nil {
+		return nil, err
+	}
+
 	for i := 0; i < argumentsCount; i++ {
 		abstraction := reflectedFunction.In(i)
 
-		if abstraction.Implements(contextType) {
-			arguments[i] = reflect.ValueOf(ctx)
+		if value, ok := supplied[i]; ok {
+			arguments[i] = value
+		} else if abstraction.Implements(contextType) {
+			arguments[i] = reflect.ValueOf(resolverContext(ctx))
+		} else if abstraction == containerType {
+			arguments[i] = reflect.ValueOf(c)
+		} else if isParameterObject(abstraction) {
+			parameters := reflect.New(abstraction).Elem()
+			if err := c.fill(ctx, parameters); err != nil {
+				return nil, fmt.Errorf("%w for type '%s', Error: %w", ErrResolutionFailed, abstraction.String(), err)
+			}
+			arguments[i] = parameters
 		} else {
 			if instance, err := c.make(ctx, abstraction, ""); err == nil {
-				arguments[i] = reflect.ValueOf(instance)
+				arguments[i] = valueOf(abstraction, instance)
 			} else {
 				return nil, fmt.Errorf("%w for type '%s', Error: %w", ErrResolutionFailed, abstraction.String(), err)
 			}
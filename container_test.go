@@ -3,6 +3,7 @@ package container_test
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -1070,3 +1071,303 @@ func NewAppAction(args []string) Action {
 func (a AppAction) Run(ctx context.Context) error {
 	return nil
 }
+
+type Disposable struct {
+	name   string
+	closed *[]string
+	err    error
+}
+
+func (d *Disposable) Close() error {
+	*d.closed = append(*d.closed, d.name)
+	return d.err
+}
+
+func TestContainer_Close_Disposes_Scoped_Concretes(t *testing.T) {
+	root := container.New(container.WithDisposal())
+	closed := []string{}
+
+	err := root.RegisterSingleton(func() *Disposable {
+		return &Disposable{name: "singleton", closed: &closed}
+	})
+	assert.NoError(t, err)
+
+	err = root.RegisterScoped(func(d *Disposable) io.Closer {
+		return &Disposable{name: "scoped", closed: &closed}
+	})
+	assert.NoError(t, err)
+
+	scope, err := root.NewScope()
+	assert.NoError(t, err)
+
+	var closer io.Closer
+	err = scope.Resolve(context.Background(), &closer)
+	assert.NoError(t, err)
+
+	err = scope.Close()
+	assert.NoError(t, err)
+
+	// The singleton is owned by the root container and outlives the scope.
+	assert.Equal(t, []string{"scoped"}, closed)
+
+	err = root.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"scoped", "singleton"}, closed)
+
+	// Closing again has no effect.
+	err = root.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"scoped", "singleton"}, closed)
+}
+
+func TestContainer_Close_Disposes_In_Reverse_Order(t *testing.T) {
+	c := container.New(container.WithDisposal())
+	closed := []string{}
+	closeErr := errors.New("app: close failed")
+
+	err := c.RegisterScoped(func() *Disposable {
+		return &Disposable{name: "first", closed: &closed, err: closeErr}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterTransient(func(d *Disposable) io.Closer {
+		return &Disposable{name: "second", closed: &closed}
+	})
+	assert.NoError(t, err)
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	var closer io.Closer
+	err = scope.Resolve(context.Background(), &closer)
+	assert.NoError(t, err)
+
+	err = scope.Close()
+	assert.ErrorIs(t, err, closeErr)
+	assert.Equal(t, []string{"second", "first"}, closed)
+}
+
+func TestContainer_Close_Does_Not_Dispose_Transients_Of_The_Root(t *testing.T) {
+	metrics := container.NewMemoryMetrics()
+	c := container.New(container.WithMetrics(metrics), container.WithDisposal())
+	closed := []string{}
+
+	err := c.RegisterTransient(func() io.Closer {
+		return &Disposable{name: "transient", closed: &closed}
+	})
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		var closer io.Closer
+		assert.NoError(t, c.Resolve(context.Background(), &closer))
+	}
+	assert.Equal(t, 0, metrics.Snapshot().OpenDisposables)
+
+	assert.NoError(t, c.Close())
+	assert.Empty(t, closed)
+}
+
+func TestContainer_Close_Without_Disposal(t *testing.T) {
+	c := container.New()
+	closed := []string{}
+
+	err := c.RegisterScoped(func() *Disposable {
+		return &Disposable{name: "scoped", closed: &closed}
+	})
+	assert.NoError(t, err)
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	var d *Disposable
+	assert.NoError(t, scope.Resolve(context.Background(), &d))
+
+	assert.NoError(t, scope.Close())
+	assert.NoError(t, c.Close())
+	assert.Empty(t, closed)
+	assert.Equal(t, 0, c.ActiveScopes())
+}
+
+func TestContainer_Close_Does_Not_Dispose_Instances(t *testing.T) {
+	c := container.New(container.WithDisposal())
+	closed := []string{}
+
+	err := c.RegisterInstance(&Disposable{name: "instance", closed: &closed})
+	assert.NoError(t, err)
+
+	var d *Disposable
+	err = c.Resolve(context.Background(), &d)
+	assert.NoError(t, err)
+
+	err = c.Close()
+	assert.NoError(t, err)
+	assert.Empty(t, closed)
+}
+
+type Cache struct {
+	DB    Database `container:"type"`
+	inits int
+	err   error
+}
+
+func (c *Cache) Init(ctx context.Context) error {
+	c.inits++
+	return c.err
+}
+
+func TestContainer_Resolve_Calls_Init_Once(t *testing.T) {
+	c := container.New()
+	container.MustRegisterSingleton(c, func() Database {
+		return &MySQL{}
+	})
+	container.MustRegisterStruct[*Cache](c, container.Singleton)
+
+	var first, second *Cache
+	assert.NoError(t, c.Resolve(context.Background(), &first))
+	assert.NoError(t, c.Resolve(context.Background(), &second))
+	assert.Same(t, first, second)
+	assert.NotNil(t, first.DB)
+	assert.Equal(t, 1, first.inits)
+}
+
+func TestContainer_Resolve_Calls_Init_For_Every_Transient(t *testing.T) {
+	c := container.New()
+	container.MustRegisterTransient(c, func() *Cache {
+		return &Cache{}
+	})
+
+	var first, second *Cache
+	assert.NoError(t, c.Resolve(context.Background(), &first))
+	assert.NoError(t, c.Resolve(context.Background(), &second))
+	assert.Equal(t, 1, first.inits)
+	assert.Equal(t, 1, second.inits)
+}
+
+func TestContainer_RegisterInstance_Does_Not_Call_Init(t *testing.T) {
+	c := container.New()
+	container.MustRegisterInstance(c, &Cache{})
+
+	var cache *Cache
+	assert.NoError(t, c.Resolve(context.Background(), &cache))
+	assert.Equal(t, 0, cache.inits)
+}
+
+func TestContainer_Resolve_With_Failing_Init_It_Should_Fail(t *testing.T) {
+	c := container.New()
+	expected := errors.New("cache unavailable")
+	constructions := 0
+
+	container.MustRegisterSingleton(c, func() *Cache {
+		constructions++
+		return &Cache{err: expected}
+	})
+
+	var cache *Cache
+	err := c.Resolve(context.Background(), &cache)
+	assert.ErrorIs(t, err, container.ErrInitializationFailed)
+	assert.ErrorIs(t, err, expected)
+
+	// A failed initialization is not cached.
+	err = c.Resolve(context.Background(), &cache)
+	assert.ErrorIs(t, err, container.ErrInitializationFailed)
+	assert.Equal(t, 2, constructions)
+}
+
+func TestContainer_Resolve_Caches_Nil_Singleton(t *testing.T) {
+	c := container.New()
+	calls := 0
+
+	container.MustRegisterSingleton(c, func() Shape {
+		calls++
+		return nil
+	})
+	container.MustRegisterSingleton(c, func() *Circle {
+		calls++
+		return nil
+	})
+
+	for i := 0; i < 2; i++ {
+		var s Shape = &Square{}
+		assert.NoError(t, c.Resolve(context.Background(), &s))
+		assert.Nil(t, s)
+
+		circle := &Circle{}
+		assert.NoError(t, c.Resolve(context.Background(), &circle))
+		assert.Nil(t, circle)
+	}
+
+	assert.Equal(t, 2, calls)
+	for _, registration := range c.Registrations() {
+		assert.True(t, registration.Instantiated)
+	}
+}
+
+func TestContainer_Call_With_Nil_Concrete(t *testing.T) {
+	c := container.New()
+	container.MustRegisterTransient(c, func() Shape {
+		return nil
+	})
+
+	err := c.Call(context.Background(), func(s Shape) {
+		assert.Nil(t, s)
+	})
+	assert.NoError(t, err)
+
+	myApp := struct {
+		S      Shape        `container:"type"`
+		All    []Shape      `container:"group"`
+		Lazy   func() Shape `container:"lazy"`
+		Nested struct {
+			S Shape `container:"type"`
+		} `container:"fill"`
+	}{S: &Circle{}}
+
+	assert.NoError(t, c.Fill(context.Background(), &myApp))
+	assert.Nil(t, myApp.S)
+	assert.Equal(t, []Shape{nil}, myApp.All)
+	assert.Nil(t, myApp.Lazy())
+	assert.Nil(t, myApp.Nested.S)
+}
+
+func TestContainer_RegisterInstance_With_Typed_Nil(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterInstance((*Circle)(nil)))
+	assert.NoError(t, container.RegisterInstanceAs[Shape](c, nil))
+
+	circle := &Circle{}
+	assert.NoError(t, c.Resolve(context.Background(), &circle))
+	assert.Nil(t, circle)
+
+	var s Shape = &Square{}
+	assert.NoError(t, c.Resolve(context.Background(), &s))
+	assert.Nil(t, s)
+}
+
+func TestContainer_RegisterInstance_With_Untyped_Nil_It_Should_Fail(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterInstance(nil)
+	assert.ErrorIs(t, err, container.ErrInvalidAbstraction)
+
+	err = c.Register(container.RegisterOptions{Resolver: nil})
+	assert.ErrorIs(t, err, container.ErrInvalidAbstraction)
+}
+
+func TestContainer_Resolve_Does_Not_Initialize_Or_Dispose_Nil_Pointers(t *testing.T) {
+	c := container.New()
+	container.MustRegisterTransient(c, func() *Cache {
+		return nil
+	})
+	container.MustRegisterTransient(c, func() *Disposable {
+		return nil
+	})
+
+	var cache *Cache
+	assert.NoError(t, c.Resolve(context.Background(), &cache))
+	assert.Nil(t, cache)
+
+	var disposable *Disposable
+	assert.NoError(t, c.Resolve(context.Background(), &disposable))
+	assert.NoError(t, c.Close())
+}
@@ -0,0 +1,46 @@
+package container
+
+import "context"
+
+type containerKey struct{}
+
+// WithContainer returns a copy of the context carrying the container, typically a scope created for a request.
+func WithContainer(ctx context.Context, c *Container) context.Context {
+	return context.WithValue(ctx, containerKey{}, c)
+}
+
+// FromContext returns the container carried by the context, if any.
+func FromContext(ctx context.Context) (*Container, bool) {
+	c, ok := ctx.Value(containerKey{}).(*Container)
+	return c, ok && c != nil
+}
+
+// WithScopeFromContext makes the container and the scopes created from it resolve scoped bindings from the scope
+// carried by the context with WithContainer, when that scope was created from the container.
+// Without it, or when the context carries no such scope, scoped bindings are resolved from the container itself.
+func WithScopeFromContext() Option {
+	return func(c *Container) {
+		c.scopeFromContext = true
+	}
+}
+
+// contextScope returns the scope carried by the context to resolve the scoped bindings of the container from,
+// or nil when they are resolved from the container itself.
+func (c *Container) contextScope(ctx context.Context) *Container {
+	if !c.scopeFromContext {
+		return nil
+	}
+
+	scope, ok := FromContext(ctx)
+	if !ok || scope == c {
+		return nil
+	}
+
+	for ancestor := scope.parent; ancestor != nil; ancestor = ancestor.parent {
+		if ancestor == c {
+			return scope
+		}
+	}
+
+	return nil
+}
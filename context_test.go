@@ -0,0 +1,104 @@
+package container_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+func TestContainer_WithContainer(t *testing.T) {
+	c := container.New()
+
+	_, ok := container.FromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := container.WithContainer(context.Background(), c)
+	found, ok := container.FromContext(ctx)
+	assert.True(t, ok)
+	assert.Same(t, c, found)
+}
+
+func TestContainer_Resolve_With_Scope_From_Context(t *testing.T) {
+	c := container.New(container.WithScopeFromContext())
+	err := c.RegisterScoped(func() Database {
+		return &MySQL{options: &DatabaseOptions{}}
+	})
+	assert.NoError(t, err)
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+	ctx := container.WithContainer(context.Background(), scope)
+
+	var fromScope, fromContext Database
+	assert.NoError(t, scope.Resolve(context.Background(), &fromScope))
+	assert.NoError(t, c.Resolve(ctx, &fromContext))
+	assert.Same(t, fromScope, fromContext)
+
+	err = c.Call(ctx, func(db Database) {
+		assert.Same(t, fromScope, db)
+	})
+	assert.NoError(t, err)
+
+	// Without a scope in the context, the container resolves its own concrete.
+	var fromRoot Database
+	assert.NoError(t, c.Resolve(context.Background(), &fromRoot))
+	assert.NotSame(t, fromScope, fromRoot)
+}
+
+func TestContainer_Resolve_With_Scope_From_Context_Nested(t *testing.T) {
+	c := container.New(container.WithScopeFromContext())
+	err := c.RegisterScoped(func() Database {
+		return &MySQL{options: &DatabaseOptions{}}
+	})
+	assert.NoError(t, err)
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+	nested, err := scope.NewScope()
+	assert.NoError(t, err)
+
+	var fromNested, fromContext Database
+	assert.NoError(t, nested.Resolve(context.Background(), &fromNested))
+	assert.NoError(t, scope.Resolve(container.WithContainer(context.Background(), nested), &fromContext))
+	assert.Same(t, fromNested, fromContext)
+}
+
+func TestContainer_Resolve_With_Scope_From_Context_Ignores_Unrelated_Scopes(t *testing.T) {
+	c := container.New(container.WithScopeFromContext())
+	err := c.RegisterScoped(func() Database {
+		return &MySQL{options: &DatabaseOptions{}}
+	})
+	assert.NoError(t, err)
+
+	other := container.New()
+	err = other.RegisterScoped(func() Database {
+		return &MySQL{options: &DatabaseOptions{}}
+	})
+	assert.NoError(t, err)
+
+	scope, err := other.NewScope()
+	assert.NoError(t, err)
+
+	var fromScope, fromContext Database
+	assert.NoError(t, scope.Resolve(context.Background(), &fromScope))
+	assert.NoError(t, c.Resolve(container.WithContainer(context.Background(), scope), &fromContext))
+	assert.NotSame(t, fromScope, fromContext)
+}
+
+func TestContainer_Resolve_Without_Scope_From_Context(t *testing.T) {
+	c := container.New()
+	err := c.RegisterScoped(func() Database {
+		return &MySQL{options: &DatabaseOptions{}}
+	})
+	assert.NoError(t, err)
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	var fromScope, fromContext Database
+	assert.NoError(t, scope.Resolve(context.Background(), &fromScope))
+	assert.NoError(t, c.Resolve(container.WithContainer(context.Background(), scope), &fromContext))
+	assert.NotSame(t, fromScope, fromContext)
+}
@@ -0,0 +1,25 @@
+package container
+
+import "io"
+
+// WithDisposal makes the container and the scopes created from it close the concretes they construct that implement
+// io.Closer when they are closed, in reverse order of construction.
+// Singletons are owned by the container they are registered in, scoped concretes by the scope they are resolved in and
+// transient concretes by the scope they are resolved from. Transient concretes resolved from the root container and
+// instances registered as is are never closed.
+func WithDisposal() Option {
+	return func(c *Container) {
+		c.disposal = true
+	}
+}
+
+// track registers a constructed concrete to be closed along with the container.
+func (c *Container) track(closer io.Closer) {
+	c.mu.Lock()
+	c.disposables = append(c.disposables, closer)
+	c.mu.Unlock()
+
+	if c.metrics != nil {
+		c.metrics.DisposableTracked()
+	}
+}
@@ -0,0 +1,159 @@
+package container
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ExpiringOptions configures the strategy returned by NewExpiringLifetime.
+type ExpiringOptions struct {
+	// TTL is the duration a concrete is reused for once constructed. Zero means the concrete never expires by age.
+	TTL time.Duration
+	// Stale reports whether the cached concrete must be rebuilt, whatever its age. It is optional.
+	Stale func(concrete interface{}) bool
+	// Background rebuilds expired concretes in a goroutine, the expired concrete being returned until the new one is
+	// constructed. Without it, the resolution finding the concrete expired rebuilds it.
+	Background bool
+	// OnRefreshError is called with the errors of the background rebuilds, after which the expired concrete is
+	// returned until the next attempt. It is optional.
+	OnRefreshError func(err error)
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewExpiringLifetime returns the strategy of a lifetime caching a concrete per binding like Singleton until it
+// expires, after which it is rebuilt. The replaced concretes implementing io.Closer are closed, and so is the last
+// concrete when the container the binding is registered in is closed.
+//
+//	c.RegisterLifetime("hourly", container.NewExpiringLifetime(container.ExpiringOptions{TTL: time.Hour}))
+func NewExpiringLifetime(options ExpiringOptions) LifetimeStrategy {
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+
+	return &expiringLifetime{options: options, entries: map[*binding]*expiringEntry{}}
+}
+
+type expiringLifetime struct {
+	options ExpiringOptions
+
+	mu      sync.Mutex
+	entries map[*binding]*expiringEntry
+}
+
+// expiringEntry holds the concrete of a binding.
+type expiringEntry struct {
+	mu          sync.Mutex
+	concrete    interface{}
+	constructed time.Time
+	valid       bool // valid is true once a concrete is constructed.
+	refreshing  bool // refreshing is true while a background rebuild is running.
+}
+
+func (l *expiringLifetime) entry(b *binding) *expiringEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, exist := l.entries[b]
+	if !exist {
+		entry = &expiringEntry{}
+		l.entries[b] = entry
+	}
+
+	return entry
+}
+
+// expired reports whether the concrete of the entry must be rebuilt.
+func (l *expiringLifetime) expired(entry *expiringEntry) bool {
+	if l.options.TTL > 0 && l.options.Now().Sub(entry.constructed) >= l.options.TTL {
+		return true
+	}
+
+	return l.options.Stale != nil && l.options.Stale(entry.concrete)
+}
+
+func (l *expiringLifetime) Resolve(ctx context.Context, request LifetimeRequest) (interface{}, bool, error) {
+	entry := l.entry(request.binding)
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.valid && !l.expired(entry) {
+		return entry.concrete, true, nil
+	}
+
+	if entry.valid && l.options.Background {
+		if !entry.refreshing {
+			entry.refreshing = true
+			go l.refresh(context.WithoutCancel(ctx), request, entry)
+		}
+
+		return entry.concrete, true, nil
+	}
+
+	concrete, err := request.Construct(ctx)
+	if err != nil {
+		return concrete, false, err
+	}
+
+	old, replaced := entry.concrete, entry.valid
+	entry.concrete, entry.constructed, entry.valid = concrete, l.options.Now(), true
+
+	if replaced {
+		// The new concrete is returned whether the expired one closes or not.
+		_ = closeConcrete(old)
+	}
+
+	return concrete, false, nil
+}
+
+// refresh rebuilds the concrete of the entry in the background.
+func (l *expiringLifetime) refresh(ctx context.Context, request LifetimeRequest, entry *expiringEntry) {
+	concrete, err := request.Construct(ctx)
+
+	entry.mu.Lock()
+	entry.refreshing = false
+	if err != nil {
+		entry.mu.Unlock()
+
+		if l.options.OnRefreshError != nil {
+			l.options.OnRefreshError(err)
+		}
+		return
+	}
+
+	// The binding may have been disposed of while rebuilding, the new concrete is then closed right away.
+	old := concrete
+	if entry.valid {
+		old = entry.concrete
+		entry.concrete, entry.constructed = concrete, l.options.Now()
+	}
+	entry.mu.Unlock()
+
+	if err := closeConcrete(old); err != nil && l.options.OnRefreshError != nil {
+		l.options.OnRefreshError(err)
+	}
+}
+
+// dispose closes the concrete of the binding and forgets it.
+func (l *expiringLifetime) dispose(b *binding) error {
+	l.mu.Lock()
+	entry, exist := l.entries[b]
+	delete(l.entries, b)
+	l.mu.Unlock()
+
+	if !exist {
+		return nil
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if !entry.valid {
+		return nil
+	}
+
+	entry.valid = false
+	return closeConcrete(entry.concrete)
+}
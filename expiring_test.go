@@ -0,0 +1,243 @@
+package container_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+type Credentials struct {
+	Token   int
+	Revoked bool
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (c *Credentials) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	return nil
+}
+
+func (c *Credentials) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.closed
+}
+
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func resolveCredentials(t *testing.T, c *container.Container) *Credentials {
+	t.Helper()
+
+	var credentials *Credentials
+	assert.NoError(t, c.Resolve(context.Background(), &credentials))
+
+	return credentials
+}
+
+func TestContainer_Expiring_With_TTL(t *testing.T) {
+	now := &clock{now: time.Now()}
+	options := container.ExpiringOptions{TTL: time.Minute, Now: now.Now}
+
+	c := container.New()
+	err := c.RegisterLifetime("expiring", container.NewExpiringLifetime(options))
+	assert.NoError(t, err)
+
+	tokens := 0
+	err = c.Register(container.RegisterOptions{
+		Resolver: func() *Credentials {
+			tokens++
+			return &Credentials{Token: tokens}
+		},
+		Lifetime: "expiring",
+	})
+	assert.NoError(t, err)
+
+	first := resolveCredentials(t, c)
+	now.Advance(30 * time.Second)
+	assert.Same(t, first, resolveCredentials(t, c))
+
+	now.Advance(30 * time.Second)
+	second := resolveCredentials(t, c)
+	assert.Equal(t, 2, second.Token)
+	assert.True(t, first.isClosed())
+	assert.False(t, second.isClosed())
+
+	assert.NoError(t, c.Close())
+	assert.True(t, second.isClosed())
+}
+
+func TestContainer_Expiring_With_Stale_Predicate(t *testing.T) {
+	options := container.ExpiringOptions{
+		Stale: func(concrete interface{}) bool {
+			return concrete.(*Credentials).Revoked
+		},
+	}
+
+	c := container.New()
+	err := c.RegisterLifetime("expiring", container.NewExpiringLifetime(options))
+	assert.NoError(t, err)
+
+	tokens := 0
+	err = c.Register(container.RegisterOptions{
+		Resolver: func() *Credentials {
+			tokens++
+			return &Credentials{Token: tokens}
+		},
+		Lifetime: "expiring",
+	})
+	assert.NoError(t, err)
+
+	first := resolveCredentials(t, c)
+	assert.Same(t, first, resolveCredentials(t, c))
+
+	first.Revoked = true
+	second := resolveCredentials(t, c)
+	assert.Equal(t, 2, second.Token)
+	assert.True(t, first.isClosed())
+}
+
+func TestContainer_Expiring_With_Background_Refresh(t *testing.T) {
+	now := &clock{now: time.Now()}
+	options := container.ExpiringOptions{TTL: time.Minute, Background: true, Now: now.Now}
+
+	c := container.New()
+	err := c.RegisterLifetime("expiring", container.NewExpiringLifetime(options))
+	assert.NoError(t, err)
+
+	mu := sync.Mutex{}
+	tokens := 0
+	err = c.Register(container.RegisterOptions{
+		Resolver: func() *Credentials {
+			mu.Lock()
+			defer mu.Unlock()
+
+			tokens++
+			return &Credentials{Token: tokens}
+		},
+		Lifetime: "expiring",
+	})
+	assert.NoError(t, err)
+
+	first := resolveCredentials(t, c)
+	now.Advance(time.Minute)
+
+	// The expired concrete is served while the new one is built.
+	served := resolveCredentials(t, c)
+	assert.Equal(t, 1, served.Token)
+
+	assert.Eventually(t, func() bool {
+		return resolveCredentials(t, c).Token == 2
+	}, time.Second, time.Millisecond)
+	assert.Eventually(t, first.isClosed, time.Second, time.Millisecond)
+}
+
+func TestContainer_Expiring_With_Background_Refresh_Failure(t *testing.T) {
+	now := &clock{now: time.Now()}
+	fail := false
+	failures := make(chan error, 1)
+
+	options := container.ExpiringOptions{
+		TTL:        time.Minute,
+		Background: true,
+		Now:        now.Now,
+		OnRefreshError: func(err error) {
+			failures <- err
+		},
+	}
+
+	c := container.New()
+	err := c.RegisterLifetime("expiring", container.NewExpiringLifetime(options))
+	assert.NoError(t, err)
+
+	mu := sync.Mutex{}
+	tokens := 0
+	err = c.Register(container.RegisterOptions{
+		Resolver: func() (*Credentials, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if fail {
+				return nil, errors.New("token service unavailable")
+			}
+
+			tokens++
+			return &Credentials{Token: tokens}, nil
+		},
+		Lifetime: "expiring",
+	})
+	assert.NoError(t, err)
+
+	first := resolveCredentials(t, c)
+	now.Advance(time.Minute)
+
+	mu.Lock()
+	fail = true
+	mu.Unlock()
+
+	assert.Same(t, first, resolveCredentials(t, c))
+	assert.Error(t, <-failures)
+	assert.Same(t, first, resolveCredentials(t, c))
+	assert.False(t, first.isClosed())
+}
+
+func TestContainer_Expiring_With_Failure_It_Should_Fail(t *testing.T) {
+	now := &clock{now: time.Now()}
+	fail := false
+	options := container.ExpiringOptions{TTL: time.Minute, Now: now.Now}
+
+	c := container.New()
+	err := c.RegisterLifetime("expiring", container.NewExpiringLifetime(options))
+	assert.NoError(t, err)
+
+	tokens := 0
+	err = c.Register(container.RegisterOptions{
+		Resolver: func() (*Credentials, error) {
+			if fail {
+				return nil, errors.New("token service unavailable")
+			}
+
+			tokens++
+			return &Credentials{Token: tokens}, nil
+		},
+		Lifetime: "expiring",
+	})
+	assert.NoError(t, err)
+
+	first := resolveCredentials(t, c)
+	now.Advance(time.Minute)
+
+	fail = true
+
+	var credentials *Credentials
+	err = c.Resolve(context.Background(), &credentials)
+	assert.Error(t, err)
+	assert.False(t, first.isClosed())
+}
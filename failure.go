@@ -0,0 +1,102 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// FailurePolicy decides how a binding handles the failures of its resolver.
+// The zero value calls the resolver once per resolution and never remembers a failure.
+type FailurePolicy struct {
+	// Retries is the number of attempts made after a failure within a single resolution.
+	Retries int `json:"retries,omitempty"`
+	// Backoff is the delay before the first retry, doubled before every following retry.
+	Backoff time.Duration `json:"backoff,omitempty"`
+	// Cooldown remembers a failed resolution for the duration: until it elapses, resolutions fail with the same error
+	// without calling the resolver.
+	Cooldown time.Duration `json:"cooldown,omitempty"`
+	// Permanent remembers a failed resolution forever, taking precedence over Cooldown.
+	Permanent bool `json:"permanent,omitempty"`
+	// Now returns the current time the cool-down is measured with. It is optional and defaults to time.Now.
+	Now func() time.Time `json:"-"`
+}
+
+// now returns the current time according to the policy.
+func (p FailurePolicy) now() time.Time {
+	if p.Now == nil {
+		return time.Now()
+	}
+
+	return p.Now()
+}
+
+// failure is a resolution failure remembered by a binding.
+type failure struct {
+	err   error
+	until time.Time // until is the end of the cool-down, zero for a permanent failure.
+}
+
+// active reports whether the failure still applies at the time.
+func (f *failure) active(now time.Time) bool {
+	return f != nil && (f.until.IsZero() || now.Before(f.until))
+}
+
+// remembered returns the failure remembered by the binding, if it still applies.
+func (b *binding) remembered() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.failure.active(b.failurePolicy.now()) {
+		b.failure = nil
+		return nil
+	}
+
+	return b.failure.err
+}
+
+// remember records the failure according to the failure policy of the binding.
+// Failures caused by the context of the resolution are not remembered, they only concern its caller.
+func (b *binding) remember(ctx context.Context, err error) {
+	policy := b.failurePolicy
+	if !policy.Permanent && policy.Cooldown <= 0 {
+		return
+	}
+
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+
+	f := &failure{err: err}
+	if !policy.Permanent {
+		f.until = policy.now().Add(policy.Cooldown)
+	}
+
+	b.mu.Lock()
+	b.failure = f
+	b.mu.Unlock()
+}
+
+// attempt calls construct until it succeeds or the retries of the failure policy are exhausted, waiting for the
+// backoff between attempts. It stops early when the context is done.
+func (b *binding) attempt(ctx context.Context, construct func() (interface{}, error)) (interface{}, error) {
+	backoff := b.failurePolicy.Backoff
+
+	for retry := 0; ; retry++ {
+		concrete, err := construct()
+		if err == nil || retry >= b.failurePolicy.Retries {
+			return concrete, err
+		}
+
+		if backoff > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return concrete, err
+			case <-timer.C:
+			}
+			backoff *= 2
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package container_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+func TestContainer_Resolve_With_Retries(t *testing.T) {
+	attempts := 0
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() (Shape, error) {
+			attempts++
+			if attempts <= 2 {
+				return nil, errors.New("dependency down")
+			}
+
+			return &Circle{a: attempts}, nil
+		},
+		FailurePolicy: container.FailurePolicy{Retries: 2, Backoff: time.Millisecond},
+	})
+	assert.NoError(t, err)
+
+	var s Shape
+	assert.NoError(t, c.Resolve(context.Background(), &s))
+	assert.Equal(t, 3, s.GetArea())
+	assert.Equal(t, 3, c.Registrations()[0].Constructions)
+}
+
+func TestContainer_Resolve_With_Exhausted_Retries_It_Should_Fail(t *testing.T) {
+	attempts := 0
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() (Shape, error) {
+			attempts++
+			if attempts <= 5 {
+				return nil, errors.New("dependency down")
+			}
+
+			return &Circle{a: attempts}, nil
+		},
+		FailurePolicy: container.FailurePolicy{Retries: 1},
+	})
+	assert.NoError(t, err)
+
+	var s Shape
+	assert.Error(t, c.Resolve(context.Background(), &s))
+	assert.Equal(t, 2, attempts)
+}
+
+func TestContainer_Resolve_With_Retries_Stops_When_Context_Is_Done(t *testing.T) {
+	attempts := 0
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() (Shape, error) {
+			attempts++
+			if attempts <= 5 {
+				return nil, errors.New("dependency down")
+			}
+
+			return &Circle{a: attempts}, nil
+		},
+		FailurePolicy: container.FailurePolicy{Retries: 3, Backoff: time.Hour},
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var s Shape
+	assert.Error(t, c.Resolve(ctx, &s))
+	assert.Equal(t, 1, attempts)
+}
+
+func TestContainer_Resolve_With_Cooldown(t *testing.T) {
+	attempts := 0
+	now := &clock{now: time.Now()}
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() (Shape, error) {
+			attempts++
+			if attempts <= 1 {
+				return nil, errors.New("dependency down")
+			}
+
+			return &Circle{a: attempts}, nil
+		},
+		FailurePolicy: container.FailurePolicy{Cooldown: time.Minute, Now: now.Now},
+	})
+	assert.NoError(t, err)
+
+	var s Shape
+	first := c.Resolve(context.Background(), &s)
+	assert.Error(t, first)
+
+	second := c.Resolve(context.Background(), &s)
+	assert.Equal(t, first.Error(), second.Error())
+	assert.Equal(t, 1, attempts)
+	assert.True(t, c.Registrations()[0].Failing)
+
+	now.Advance(time.Minute)
+	assert.False(t, c.Registrations()[0].Failing)
+	assert.NoError(t, c.Resolve(context.Background(), &s))
+	assert.Equal(t, 2, attempts)
+}
+
+func TestContainer_Resolve_With_Permanent_Failure(t *testing.T) {
+	attempts := 0
+	policy := container.FailurePolicy{Retries: 1, Permanent: true}
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() (Shape, error) {
+			attempts++
+			if attempts <= 2 {
+				return nil, errors.New("dependency down")
+			}
+
+			return &Circle{a: attempts}, nil
+		},
+		FailurePolicy: policy,
+	})
+	assert.NoError(t, err)
+
+	var s Shape
+	for i := 0; i < 3; i++ {
+		assert.Error(t, c.Resolve(context.Background(), &s))
+	}
+	assert.Equal(t, 2, attempts)
+
+	registration := c.Registrations()[0]
+	assert.Equal(t, policy, registration.FailurePolicy)
+	assert.True(t, registration.Failing)
+}
+
+func TestContainer_Resolve_Without_Failure_Policy_Retries_On_Next_Resolve(t *testing.T) {
+	attempts := 0
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() (Shape, error) {
+			attempts++
+			if attempts <= 1 {
+				return nil, errors.New("dependency down")
+			}
+
+			return &Circle{a: attempts}, nil
+		},
+		FailurePolicy: container.FailurePolicy{},
+	})
+	assert.NoError(t, err)
+
+	var s Shape
+	assert.Error(t, c.Resolve(context.Background(), &s))
+	assert.NoError(t, c.Resolve(context.Background(), &s))
+	assert.Equal(t, 2, attempts)
+	assert.False(t, c.Registrations()[0].Failing)
+}
+
+func TestContainer_Resolve_With_Permanent_Failure_Ignores_Cancellations(t *testing.T) {
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func(ctx context.Context) (Shape, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, fmt.Errorf("connecting: %w", err)
+			}
+
+			return &Circle{a: 1}, nil
+		},
+		FailurePolicy: container.FailurePolicy{Permanent: true},
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var s Shape
+	assert.ErrorIs(t, c.Resolve(ctx, &s), context.Canceled)
+	assert.False(t, c.Registrations()[0].Failing)
+
+	assert.NoError(t, c.Resolve(context.Background(), &s))
+	assert.Equal(t, 1, s.GetArea())
+}
+
+func TestContainer_Resolve_With_Cooldown_Ignores_Deadlines(t *testing.T) {
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() (Shape, error) {
+			return nil, fmt.Errorf("dialing: %w", context.DeadlineExceeded)
+		},
+		FailurePolicy: container.FailurePolicy{Cooldown: time.Hour},
+	})
+	assert.NoError(t, err)
+
+	var s Shape
+	assert.ErrorIs(t, c.Resolve(context.Background(), &s), context.DeadlineExceeded)
+	assert.False(t, c.Registrations()[0].Failing)
+}
@@ -0,0 +1,409 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// Parameters is embedded in a struct to turn it into a parameter object.
+// When a resolver or a receiver declares a parameter of such a struct type, the container creates the struct and
+// fills its fields according to their `container` tags, like Fill does, instead of resolving the struct itself.
+//
+//	type ServerParameters struct {
+//		container.Parameters
+//
+//		Primary Database `container:"name=primary"`
+//		Cache   Cache    `container:"type,optional"`
+//		Plugins []Plugin `container:"group"`
+//	}
+type Parameters struct{}
+
+var parametersType = reflect.TypeOf(Parameters{})
+
+// fieldTag is the parsed value of the `container` tag of a struct field.
+//
+// The tag is a comma separated list of options:
+//
+//	type       resolve the binding registered without a name (default when no name is given)
+//	name       resolve the binding named after the field
+//	name=x     resolve the binding named x
+//	optional   leave the field untouched when no binding is registered
+//	group      fill a slice field with the concretes of every binding of the element type, sorted by name
+//	lazy       fill a `func() T` or `func() (T, error)` field with a function resolving the binding when called
+//	fill       fill the fields of a nested struct or pointer to struct field, allocating it if it is nil
+//	-          skip the field
+//
+// Embedded struct and pointer to struct fields without a tag are filled as if they were tagged with `fill`.
+type fieldTag struct {
+	name     string
+	skip     bool
+	optional bool
+	group    bool
+	lazy     bool
+	fill     bool
+}
+
+// parseFieldTag parses the `container` tag of a struct field.
+// The second return value reports whether the field has a `container` tag at all.
+func parseFieldTag(field reflect.StructField) (fieldTag, bool, error) {
+	value, exist := field.Tag.Lookup("container")
+	if !exist {
+		return fieldTag{}, false, nil
+	}
+
+	invalid := func(format string, args ...interface{}) (fieldTag, bool, error) {
+		return fieldTag{}, true, fmt.Errorf("%w, %v has an invalid struct tag '%s': %s",
+			ErrInvalidStructure, field.Name, value, fmt.Sprintf(format, args...))
+	}
+
+	if value == "-" {
+		return fieldTag{skip: true}, true, nil
+	}
+
+	var tag fieldTag
+	seen := map[string]bool{}
+	named := false
+
+	for _, option := range strings.Split(value, ",") {
+		key, argument, hasArgument := strings.Cut(strings.TrimSpace(option), "=")
+		if seen[key] {
+			return invalid("duplicate option '%s'", key)
+		}
+		seen[key] = true
+
+		if hasArgument && key != "name" {
+			return invalid("option '%s' does not take a value", key)
+		}
+
+		switch key {
+		case "type":
+		case "name":
+			named = true
+			tag.name = field.Name
+			if hasArgument {
+				if argument == "" {
+					return invalid("the name is empty")
+				}
+				tag.name = argument
+			}
+		case "optional":
+			tag.optional = true
+		case "group":
+			tag.group = true
+		case "lazy":
+			tag.lazy = true
+		case "fill":
+			tag.fill = true
+		case "":
+			return invalid("empty option")
+		default:
+			return invalid("unknown option '%s'", key)
+		}
+	}
+
+	if seen["type"] && named {
+		return invalid("'type' and 'name' are exclusive")
+	}
+
+	if tag.group && (named || tag.lazy || tag.optional) {
+		return invalid("'group' cannot be combined with 'name', 'lazy' or 'optional'")
+	}
+
+	if tag.group && field.Type.Kind() != reflect.Slice {
+		return invalid("'group' requires a slice field")
+	}
+
+	if tag.lazy && lazyTarget(field.Type) == nil {
+		return invalid("'lazy' requires a field of type func() T or func() (T, error)")
+	}
+
+	if tag.fill && len(seen) > 1 {
+		return invalid("'fill' cannot be combined with other options")
+	}
+
+	if tag.fill && nestedStruct(field.Type) == nil {
+		return invalid("'fill' requires a struct or pointer to struct field")
+	}
+
+	return tag, true, nil
+}
+
+// nestedStruct returns the struct type of a struct or pointer to struct type, or nil for any other type.
+func nestedStruct(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return t
+}
+
+// isNested reports whether the field is filled recursively, either because it is tagged with `fill` or because it
+// is an untagged embedded struct or pointer to struct.
+func isNested(field reflect.StructField, tag fieldTag, tagged bool) bool {
+	if tagged {
+		return tag.fill
+	}
+
+	return field.Anonymous && nestedStruct(field.Type) != nil
+}
+
+// hasInjectableFields reports whether filling a struct of the type would resolve at least one field.
+func hasInjectableFields(structType reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[structType] {
+		return false
+	}
+	seen[structType] = true
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		tag, tagged, err := parseFieldTag(field)
+		if tagged && (err != nil || tag.skip) {
+			continue
+		}
+
+		if isNested(field, tag, tagged) {
+			if hasInjectableFields(nestedStruct(field.Type), seen) {
+				return true
+			}
+			continue
+		}
+
+		if tagged {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dependency returns the type of the binding the field depends on.
+func (tag fieldTag) dependency(fieldType reflect.Type) reflect.Type {
+	if tag.group {
+		return fieldType.Elem()
+	}
+
+	if tag.lazy {
+		return lazyTarget(fieldType)
+	}
+
+	return fieldType
+}
+
+// lazyTarget returns the type resolved by a lazy field, or nil if the field type is not a valid lazy function.
+func lazyTarget(fieldType reflect.Type) reflect.Type {
+	if fieldType.Kind() != reflect.Func || fieldType.NumIn() != 0 {
+		return nil
+	}
+
+	switch fieldType.NumOut() {
+	case 1:
+		return fieldType.Out(0)
+	case 2:
+		if fieldType.Out(1) == errorType {
+			return fieldType.Out(0)
+		}
+	}
+
+	return nil
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// isParameterObject reports whether the type is a struct embedding Parameters.
+func isParameterObject(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous && field.Type == parametersType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fill resolves the tagged fields of the addressable struct value and recurses into its nested structs.
+func (c *Container) fill(ctx context.Context, s reflect.Value) error {
+	state := &fillState{visited: map[fillKey]bool{}, path: map[reflect.Type]bool{}}
+
+	return c.fillStruct(ctx, s, state)
+}
+
+// fillKey identifies a struct being filled. The type is part of the key since an embedded struct shares the address
+// of the struct embedding it.
+type fillKey struct {
+	address uintptr
+	t       reflect.Type
+}
+
+// fillState protects a recursive fill against cycles.
+type fillState struct {
+	visited map[fillKey]bool      // visited holds the structs already filled, reached through a pointer or not.
+	path    map[reflect.Type]bool // path holds the struct types being filled, from the root to the current struct.
+}
+
+func (c *Container) fillStruct(ctx context.Context, s reflect.Value, state *fillState) error {
+	key := fillKey{address: s.Addr().Pointer(), t: s.Type()}
+	if state.visited[key] {
+		return nil
+	}
+
+	state.visited[key] = true
+	state.path[s.Type()] = true
+	defer delete(state.path, s.Type())
+
+	for i := 0; i < s.NumField(); i++ {
+		field := s.Type().Field(i)
+
+		tag, tagged, err := parseFieldTag(field)
+		if tagged && err != nil {
+			return err
+		}
+
+		if tagged && tag.skip {
+			continue
+		}
+
+		f := s.Field(i)
+		f = reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+
+		if isNested(field, tag, tagged) {
+			if err := c.fillNested(ctx, field, f, state); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !tagged {
+			continue
+		}
+
+		value, err := c.fieldValue(ctx, field, tag)
+		if err != nil {
+			return fmt.Errorf("%w for field '%v', Error: %w", ErrResolutionFailed, field.Name, err)
+		}
+
+		if value.IsValid() {
+			f.Set(value)
+		}
+	}
+
+	return nil
+}
+
+// fillNested fills a nested struct or pointer to struct field.
+// Nil pointers are allocated when the struct has fields to inject, unless the struct type is already being filled
+// higher in the path, which would allocate structs forever.
+func (c *Container) fillNested(ctx context.Context, field reflect.StructField, f reflect.Value, state *fillState) error {
+	if f.Kind() == reflect.Struct {
+		return c.fillStruct(ctx, f, state)
+	}
+
+	if f.IsNil() {
+		if !hasInjectableFields(f.Type().Elem(), map[reflect.Type]bool{}) {
+			return nil
+		}
+
+		if state.path[f.Type().Elem()] {
+			return fmt.Errorf("%w, cycle detected allocating field '%v' of type '%s'", ErrInvalidStructure, field.Name, f.Type().String())
+		}
+
+		f.Set(reflect.New(f.Type().Elem()))
+	}
+
+	return c.fillStruct(ctx, f.Elem(), state)
+}
+
+// fieldValue resolves the value of a tagged field.
+// It returns an invalid value when an optional field has no binding.
+func (c *Container) fieldValue(ctx context.Context, field reflect.StructField, tag fieldTag) (reflect.Value, error) {
+	dependency := tag.dependency(field.Type)
+
+	if tag.optional {
+		if binding, _ := c.lookup(dependency, tag.name); binding == nil {
+			return reflect.Value{}, nil
+		}
+	}
+
+	if tag.group {
+		return c.group(ctx, field.Type)
+	}
+
+	if tag.lazy {
+		return c.lazy(ctx, field.Type, tag.name), nil
+	}
+
+	instance, err := c.make(ctx, dependency, tag.name)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return valueOf(dependency, instance), nil
+}
+
+// group resolves every binding of the element type of the slice type, sorted by name.
+func (c *Container) group(ctx context.Context, sliceType reflect.Type) (reflect.Value, error) {
+	names := []string{}
+	for _, visible := range c.visibleBindings() {
+		if visible.t == sliceType.Elem() {
+			names = append(names, visible.name)
+		}
+	}
+	sort.Strings(names)
+
+	values := reflect.MakeSlice(sliceType, 0, len(names))
+	for _, name := range names {
+		instance, err := c.make(ctx, sliceType.Elem(), name)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		values = reflect.Append(values, valueOf(sliceType.Elem(), instance))
+	}
+
+	return values, nil
+}
+
+// lazy returns a function of the lazy type resolving the binding when called.
+// Functions without an error result panic when the resolution fails.
+func (c *Container) lazy(ctx context.Context, lazyType reflect.Type, name string) reflect.Value {
+	target := lazyTarget(lazyType)
+
+	// The function is called once the concrete being filled is constructed, outside of the resolutions in progress.
+	ctx = resolverContext(ctx)
+
+	return reflect.MakeFunc(lazyType, func([]reflect.Value) []reflect.Value {
+		instance, err := c.make(ctx, target, name)
+
+		value := reflect.New(target).Elem()
+		if err == nil {
+			value.Set(valueOf(target, instance))
+		}
+
+		if lazyType.NumOut() == 1 {
+			if err != nil {
+				panic(fmt.Errorf("%w for type '%s', Error: %w", ErrResolutionFailed, target.String(), err))
+			}
+
+			return []reflect.Value{value}
+		}
+
+		errValue := reflect.New(errorType).Elem()
+		if err != nil {
+			errValue.Set(reflect.ValueOf(fmt.Errorf("%w for type '%s', Error: %w", ErrResolutionFailed, target.String(), err)))
+		}
+
+		return []reflect.Value{value, errValue}
+	})
+}
@@ -0,0 +1,663 @@
+package container_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+func TestContainer_Fill_With_Explicit_Name(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 1}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("square", func() Shape {
+		return &Square{a: 2}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("big", func() Shape {
+		return &Circle{a: 3}
+	})
+	assert.NoError(t, err)
+
+	myApp := struct {
+		S Shape `container:"name=square"`
+		T Shape `container:"type"`
+	}{}
+
+	err = c.Fill(context.Background(), &myApp)
+	assert.NoError(t, err)
+	assert.IsType(t, &Square{}, myApp.S)
+	assert.IsType(t, &Circle{}, myApp.T)
+}
+
+func TestContainer_Fill_With_Optional(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 1}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("square", func() Shape {
+		return &Square{a: 2}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("big", func() Shape {
+		return &Circle{a: 3}
+	})
+	assert.NoError(t, err)
+
+	existing := &MySQL{}
+
+	myApp := struct {
+		D     Database `container:"type,optional"`
+		Other Database `container:"name=other,optional"`
+		S     Shape    `container:"name=square,optional"`
+	}{Other: existing}
+
+	err = c.Fill(context.Background(), &myApp)
+	assert.NoError(t, err)
+	assert.Nil(t, myApp.D)
+	assert.Same(t, existing, myApp.Other)
+	assert.IsType(t, &Square{}, myApp.S)
+}
+
+func TestContainer_Fill_With_Optional_Failing_Resolver_It_Should_Fail(t *testing.T) {
+	c := container.New()
+	container.MustRegisterSingleton(c, func(options *DatabaseOptions) Database {
+		return &MySQL{options: options}
+	})
+
+	myApp := struct {
+		D Database `container:"optional"`
+	}{}
+
+	err := c.Fill(context.Background(), &myApp)
+	assert.ErrorIs(t, err, container.ErrResolutionFailed)
+	assert.ErrorIs(t, err, container.ErrBindingNotFound)
+}
+
+func TestContainer_Fill_With_Group(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 1}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("square", func() Shape {
+		return &Square{a: 2}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("big", func() Shape {
+		return &Circle{a: 3}
+	})
+	assert.NoError(t, err)
+
+	myApp := struct {
+		Shapes []Shape    `container:"group"`
+		None   []Database `container:"group"`
+	}{}
+
+	err = c.Fill(context.Background(), &myApp)
+	assert.NoError(t, err)
+
+	// Sorted by name, the unnamed binding first.
+	assert.Len(t, myApp.Shapes, 3)
+	assert.Equal(t, 1, myApp.Shapes[0].GetArea())
+	assert.Equal(t, 3, myApp.Shapes[1].GetArea())
+	assert.Equal(t, 2, myApp.Shapes[2].GetArea())
+
+	assert.NotNil(t, myApp.None)
+	assert.Empty(t, myApp.None)
+}
+
+func TestContainer_Fill_With_Lazy(t *testing.T) {
+	c := container.New()
+	resolved := 0
+
+	container.MustRegisterSingleton(c, func() Shape {
+		resolved++
+		return &Circle{a: 5}
+	})
+
+	myApp := struct {
+		S       func() Shape             `container:"lazy"`
+		E       func() (Shape, error)    `container:"lazy,type"`
+		Missing func() (Database, error) `container:"lazy"`
+		Panics  func() Database          `container:"lazy"`
+	}{}
+
+	err := c.Fill(context.Background(), &myApp)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resolved)
+
+	assert.Equal(t, 5, myApp.S().GetArea())
+	assert.Equal(t, 1, resolved)
+
+	s, err := myApp.E()
+	assert.NoError(t, err)
+	assert.Equal(t, 5, s.GetArea())
+
+	db, err := myApp.Missing()
+	assert.Nil(t, db)
+	assert.ErrorIs(t, err, container.ErrBindingNotFound)
+
+	assert.Panics(t, func() {
+		myApp.Panics()
+	})
+}
+
+func TestContainer_Fill_With_Skipped_Field(t *testing.T) {
+	c := container.New()
+
+	myApp := struct {
+		D Database `container:"-"`
+	}{}
+
+	err := c.Fill(context.Background(), &myApp)
+	assert.NoError(t, err)
+	assert.Nil(t, myApp.D)
+}
+
+func TestContainer_Fill_With_Malformed_Tags_It_Should_Fail(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 1}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("square", func() Shape {
+		return &Square{a: 2}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("big", func() Shape {
+		return &Circle{a: 3}
+	})
+	assert.NoError(t, err)
+
+	tests := map[string]interface{}{
+		"empty": &struct {
+			S Shape `container:""`
+		}{},
+		"unknown option": &struct {
+			S Shape `container:"type,eager"`
+		}{},
+		"empty option": &struct {
+			S Shape `container:"type,"`
+		}{},
+		"duplicate option": &struct {
+			S Shape `container:"optional,optional"`
+		}{},
+		"empty name": &struct {
+			S Shape `container:"name="`
+		}{},
+		"type and name": &struct {
+			S Shape `container:"type,name=square"`
+		}{},
+		"unexpected value": &struct {
+			S Shape `container:"optional=true"`
+		}{},
+		"group not slice": &struct {
+			S Shape `container:"group"`
+		}{},
+		"group and name": &struct {
+			S []Shape `container:"group,name=square"`
+		}{},
+		"lazy not func": &struct {
+			S Shape `container:"lazy"`
+		}{},
+		"lazy with params": &struct {
+			S func(int) Shape `container:"lazy"`
+		}{},
+	}
+
+	for name, structure := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := c.Fill(context.Background(), structure)
+			assert.ErrorIs(t, err, container.ErrInvalidStructure)
+		})
+	}
+}
+
+type ShapeParameters struct {
+	container.Parameters
+
+	Default Shape    `container:"type"`
+	Square  Shape    `container:"name=square"`
+	All     []Shape  `container:"group"`
+	DB      Database `container:"optional"`
+	ignored int
+}
+
+func TestContainer_Resolver_With_Parameter_Object(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 1}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("square", func() Shape {
+		return &Square{a: 2}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("big", func() Shape {
+		return &Circle{a: 3}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterSingleton(func(params ShapeParameters) *DatabaseOptions {
+		assert.Equal(t, 1, params.Default.GetArea())
+		assert.Equal(t, 2, params.Square.GetArea())
+		assert.Len(t, params.All, 3)
+		assert.Nil(t, params.DB)
+		assert.Equal(t, 0, params.ignored)
+
+		return &DatabaseOptions{Port: params.Square.GetArea()}
+	})
+	assert.NoError(t, err)
+
+	var options *DatabaseOptions
+	err = c.Resolve(context.Background(), &options)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, options.Port)
+}
+
+func TestContainer_Call_With_Parameter_Object(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 1}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("square", func() Shape {
+		return &Square{a: 2}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("big", func() Shape {
+		return &Circle{a: 3}
+	})
+	assert.NoError(t, err)
+
+	err = c.Call(context.Background(), func(ctx context.Context, params ShapeParameters) {
+		assert.Equal(t, 2, params.Square.GetArea())
+	})
+	assert.NoError(t, err)
+
+	type Broken struct {
+		container.Parameters
+
+		DB Database `container:"type"`
+	}
+
+	err = c.Call(context.Background(), func(params Broken) {})
+	assert.ErrorIs(t, err, container.ErrResolutionFailed)
+	assert.ErrorIs(t, err, container.ErrBindingNotFound)
+}
+
+func TestGraph_Parameter_Object_Edges(t *testing.T) {
+	c := container.New()
+
+	container.MustRegisterNamedSingleton(c, "square", func() Shape {
+		return &Square{a: 2}
+	})
+	container.MustRegisterSingleton(c, func(params ShapeParameters) *DatabaseOptions {
+		return &DatabaseOptions{}
+	})
+
+	assert.Equal(t, []container.GraphEdge{
+		{From: "*" + testPackage + ".DatabaseOptions", To: testPackage + ".Database", Kind: container.EdgeField, Field: "DB", Optional: true},
+		{From: "*" + testPackage + ".DatabaseOptions", To: testPackage + ".Shape", Kind: container.EdgeField, Field: "Default", Unresolved: true},
+		{From: "*" + testPackage + ".DatabaseOptions", To: testPackage + ".Shape#square", Kind: container.EdgeField, Field: "All"},
+		{From: "*" + testPackage + ".DatabaseOptions", To: testPackage + ".Shape#square", Kind: container.EdgeField, Field: "Square"},
+	}, c.Graph().Edges)
+}
+
+type StorageConfig struct {
+	DB      Database `container:"type"`
+	Timeout int
+}
+
+type BaseHandler struct {
+	Shape Shape `container:"type"`
+}
+
+type Node struct {
+	Shape Shape `container:"type"`
+	Next  *Node `container:"fill"`
+}
+
+func TestContainer_Fill_Embedded_Structs(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 5}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterSingleton(func() Database {
+		return &MySQL{}
+	})
+	assert.NoError(t, err)
+
+	myApp := struct {
+		BaseHandler
+		*StorageConfig
+	}{}
+
+	err = c.Fill(context.Background(), &myApp)
+	assert.NoError(t, err)
+	assert.IsType(t, &Circle{}, myApp.Shape)
+	assert.NotNil(t, myApp.StorageConfig)
+	assert.IsType(t, &MySQL{}, myApp.DB)
+}
+
+func TestContainer_Fill_Nested_Structs(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 5}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterSingleton(func() Database {
+		return &MySQL{}
+	})
+	assert.NoError(t, err)
+
+	existing := &StorageConfig{Timeout: 30}
+
+	myApp := struct {
+		Primary   StorageConfig  `container:"fill"`
+		Secondary *StorageConfig `container:"fill"`
+		Existing  *StorageConfig `container:"fill"`
+		Ignored   StorageConfig
+		Skipped   *StorageConfig `container:"-"`
+	}{Existing: existing}
+
+	err = c.Fill(context.Background(), &myApp)
+	assert.NoError(t, err)
+
+	assert.IsType(t, &MySQL{}, myApp.Primary.DB)
+	assert.NotNil(t, myApp.Secondary)
+	assert.IsType(t, &MySQL{}, myApp.Secondary.DB)
+	assert.Same(t, existing, myApp.Existing)
+	assert.IsType(t, &MySQL{}, existing.DB)
+	assert.Equal(t, 30, existing.Timeout)
+	assert.Nil(t, myApp.Ignored.DB)
+	assert.Nil(t, myApp.Skipped)
+}
+
+func TestContainer_Fill_Does_Not_Allocate_Structs_Without_Injectable_Fields(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 5}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterSingleton(func() Database {
+		return &MySQL{}
+	})
+	assert.NoError(t, err)
+
+	myApp := struct {
+		*DatabaseOptions
+		Options *DatabaseOptions `container:"fill"`
+	}{}
+
+	err = c.Fill(context.Background(), &myApp)
+	assert.NoError(t, err)
+	assert.Nil(t, myApp.DatabaseOptions)
+	assert.Nil(t, myApp.Options)
+}
+
+func TestContainer_Fill_Nested_With_Allocation_Cycle_It_Should_Fail(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 5}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterSingleton(func() Database {
+		return &MySQL{}
+	})
+	assert.NoError(t, err)
+
+	var node Node
+	err = c.Fill(context.Background(), &node)
+	assert.ErrorIs(t, err, container.ErrInvalidStructure)
+}
+
+func TestContainer_Fill_Nested_With_Pointer_Cycle(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 5}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterSingleton(func() Database {
+		return &MySQL{}
+	})
+	assert.NoError(t, err)
+
+	first := &Node{}
+	second := &Node{Next: first}
+	first.Next = second
+
+	err = c.Fill(context.Background(), first)
+	assert.NoError(t, err)
+	assert.NotNil(t, first.Shape)
+	assert.NotNil(t, second.Shape)
+}
+
+func TestContainer_Fill_Nested_With_Invalid_Tag_It_Should_Fail(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 5}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterSingleton(func() Database {
+		return &MySQL{}
+	})
+	assert.NoError(t, err)
+
+	tests := map[string]interface{}{
+		"fill not struct": &struct {
+			S Shape `container:"fill"`
+		}{},
+		"fill and optional": &struct {
+			S StorageConfig `container:"fill,optional"`
+		}{},
+	}
+
+	for name, structure := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := c.Fill(context.Background(), structure)
+			assert.ErrorIs(t, err, container.ErrInvalidStructure)
+		})
+	}
+}
+
+func TestGraph_Nested_Field_Edges(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterInstance(&struct {
+		BaseHandler
+		Storage *StorageConfig `container:"fill"`
+		Root    *Node          `container:"fill"`
+	}{})
+	assert.NoError(t, err)
+
+	fields := []string{}
+	for _, edge := range c.Graph().Edges {
+		fields = append(fields, edge.Field)
+	}
+
+	assert.ElementsMatch(t, []string{"BaseHandler.Shape", "Storage.DB", "Root.Shape"}, fields)
+}
+
+type Repository interface {
+	Store() Database
+}
+
+type SqlRepository struct {
+	DB    Database `container:"type"`
+	Shape Shape    `container:"optional"`
+}
+
+func (r *SqlRepository) Store() Database {
+	return r.DB
+}
+
+func TestContainer_RegisterStruct(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 5}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterSingleton(func() Database {
+		return &MySQL{}
+	})
+	assert.NoError(t, err)
+
+	err = container.RegisterSingletonStruct[*SqlRepository](c)
+	assert.NoError(t, err)
+	err = container.RegisterTransientStruct[StorageConfig](c)
+	assert.NoError(t, err)
+
+	var first, second *SqlRepository
+	assert.NoError(t, c.Resolve(context.Background(), &first))
+	assert.NoError(t, c.Resolve(context.Background(), &second))
+	assert.IsType(t, &MySQL{}, first.DB)
+	assert.IsType(t, &Circle{}, first.Shape)
+	assert.Same(t, first, second)
+
+	var config StorageConfig
+	assert.NoError(t, c.Resolve(context.Background(), &config))
+	assert.IsType(t, &MySQL{}, config.DB)
+}
+
+func TestContainer_RegisterStructAs(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 5}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterSingleton(func() Database {
+		return &MySQL{}
+	})
+	assert.NoError(t, err)
+
+	err = container.RegisterStructAs[Repository, *SqlRepository](c, container.Transient)
+	assert.NoError(t, err)
+
+	var repository Repository
+	assert.NoError(t, c.Resolve(context.Background(), &repository))
+	assert.IsType(t, &SqlRepository{}, repository)
+	assert.IsType(t, &MySQL{}, repository.Store())
+
+	err = container.RegisterStructAs[Repository, SqlRepository](c, container.Transient)
+	assert.ErrorIs(t, err, container.ErrInvalidAbstraction)
+}
+
+func TestContainer_RegisterScopedStruct(t *testing.T) {
+	c := container.New()
+	container.MustRegisterScoped(c, func() Database {
+		return &MySQL{}
+	})
+
+	err := container.RegisterScopedStruct[*SqlRepository](c)
+	assert.NoError(t, err)
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	var fromScope, fromRoot *SqlRepository
+	assert.NoError(t, scope.Resolve(context.Background(), &fromScope))
+	assert.NoError(t, c.Resolve(context.Background(), &fromRoot))
+	assert.NotSame(t, fromScope, fromRoot)
+	assert.NotSame(t, fromScope.DB, fromRoot.DB)
+}
+
+func TestContainer_RegisterStruct_With_Invalid_Type_It_Should_Fail(t *testing.T) {
+	c := container.New()
+
+	err := container.RegisterStruct[Database](c, container.Singleton)
+	assert.ErrorIs(t, err, container.ErrInvalidStructure)
+
+	err = container.RegisterStruct[**SqlRepository](c, container.Singleton)
+	assert.ErrorIs(t, err, container.ErrInvalidStructure)
+}
+
+func TestContainer_RegisterStruct_With_Missing_Dependency_It_Should_Fail(t *testing.T) {
+	c := container.New()
+	container.MustRegisterStruct[*SqlRepository](c, container.Singleton)
+
+	var repository *SqlRepository
+	err := c.Resolve(context.Background(), &repository)
+	assert.ErrorIs(t, err, container.ErrBindingNotFound)
+}
+
+func TestGraph_Struct_Edges(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 5}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterSingleton(func() Database {
+		return &MySQL{}
+	})
+	assert.NoError(t, err)
+
+	container.MustRegisterStructAs[Repository, *SqlRepository](c, container.Singleton)
+
+	edges := []container.GraphEdge{}
+	for _, edge := range c.Graph().Edges {
+		if edge.From == testPackage+".Repository" {
+			edges = append(edges, edge)
+		}
+	}
+
+	assert.Equal(t, []container.GraphEdge{
+		{From: testPackage + ".Repository", To: testPackage + ".Database", Kind: container.EdgeField, Field: "DB"},
+		{From: testPackage + ".Repository", To: testPackage + ".Shape", Kind: container.EdgeField, Field: "Shape", Optional: true},
+	}, edges)
+}
+
+func TestContainer_Call_With_Container_Parameter(t *testing.T) {
+	c := container.New()
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	err = scope.Call(context.Background(), func(resolving *container.Container) {
+		assert.Same(t, scope, resolving)
+	})
+	assert.NoError(t, err)
+}
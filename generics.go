@@ -1,5 +1,11 @@
 package container
 
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
 // RegisterInstanceAs registers an instance as a specific type within the container
 func RegisterInstanceAs[T any](c *Container, instance T) error {
 	return RegisterNamedInstanceAs(c, "", instance)
@@ -17,3 +23,76 @@ func RegisterNamedInstanceAs[T any](c *Container, name string, instance T) error
 
 	return c.Register(options)
 }
+
+// RegisterStruct registers T, a struct or a pointer to a struct, without a resolver function.
+// The container allocates T and fills its tagged fields like Fill does every time it constructs a concrete.
+func RegisterStruct[T any](c *Container, lifetime Lifetime) error {
+	return RegisterNamedStructAs[T, T](c, "", lifetime)
+}
+
+// RegisterNamedStruct registers T, a struct or a pointer to a struct, with a name without a resolver function.
+func RegisterNamedStruct[T any](c *Container, name string, lifetime Lifetime) error {
+	return RegisterNamedStructAs[T, T](c, name, lifetime)
+}
+
+// RegisterSingletonStruct registers T, a struct or a pointer to a struct, as a singleton without a resolver function.
+func RegisterSingletonStruct[T any](c *Container) error {
+	return RegisterNamedStructAs[T, T](c, "", Singleton)
+}
+
+// RegisterTransientStruct registers T, a struct or a pointer to a struct, as a transient without a resolver function.
+func RegisterTransientStruct[T any](c *Container) error {
+	return RegisterNamedStructAs[T, T](c, "", Transient)
+}
+
+// RegisterScopedStruct registers T, a struct or a pointer to a struct, as a scoped binding without a resolver function.
+func RegisterScopedStruct[T any](c *Container) error {
+	return RegisterNamedStructAs[T, T](c, "", Scoped)
+}
+
+// RegisterStructAs registers T, a struct or a pointer to a struct, as the abstraction A without a resolver function.
+// T must be assignable to A, typically by implementing the interface A.
+func RegisterStructAs[A any, T any](c *Container, lifetime Lifetime) error {
+	return RegisterNamedStructAs[A, T](c, "", lifetime)
+}
+
+// RegisterNamedStructAs registers T, a struct or a pointer to a struct, as the abstraction A with a name without a
+// resolver function.
+func RegisterNamedStructAs[A any, T any](c *Container, name string, lifetime Lifetime) error {
+	abstraction := reflect.TypeOf((*A)(nil)).Elem()
+	concrete := reflect.TypeOf((*T)(nil)).Elem()
+
+	structType := nestedStruct(concrete)
+	if structType == nil {
+		return fmt.Errorf("%w, '%s' is not a struct or a pointer to a struct", ErrInvalidStructure, concrete.String())
+	}
+
+	if c.lifetimeStrategy(lifetime) == nil {
+		return fmt.Errorf("%w, no strategy registered for the lifetime '%s'", ErrInvalidLifetime, lifetime)
+	}
+
+	if !concrete.AssignableTo(abstraction) {
+		return fmt.Errorf("%w, '%s' cannot be registered as '%s'", ErrInvalidAbstraction, concrete.String(), abstraction.String())
+	}
+
+	resolver := func(ctx context.Context, scope *Container) (A, error) {
+		var instance A
+
+		value := reflect.New(structType)
+		if err := scope.fill(ctx, value.Elem()); err != nil {
+			return instance, err
+		}
+
+		if concrete.Kind() != reflect.Ptr {
+			value = value.Elem()
+		}
+
+		reflect.ValueOf(&instance).Elem().Set(value)
+
+		return instance, nil
+	}
+
+	c.register(abstraction, name, &binding{resolver: resolver, lifetime: lifetime, structType: structType})
+
+	return nil
+}
@@ -55,6 +55,11 @@ func Call(ctx context.Context, receiver interface{}) error {
 	return Global.Call(ctx, receiver)
 }
 
+// CallWithResults calls the same method of the global concrete.
+func CallWithResults(ctx context.Context, function interface{}, arguments ...Argument) ([]interface{}, error) {
+	return Global.CallWithResults(ctx, function, arguments...)
+}
+
 // Resolve calls the same method of the global concrete.
 func Resolve(ctx context.Context, abstraction interface{}) error {
 	return Global.Resolve(ctx, abstraction)
@@ -83,6 +83,14 @@ func TestCall(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCallWithResults(t *testing.T) {
+	container.Reset()
+
+	results, err := container.CallWithResults(context.Background(), func() int { return 7 })
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{7}, results)
+}
+
 func TestResolve(t *testing.T) {
 	container.Reset()
 
@@ -0,0 +1,383 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// GraphVersion is the version of the JSON schema written by Graph.WriteJSON.
+// It is bumped whenever a field is removed or changes meaning.
+const GraphVersion = 1
+
+// ErrInvalidGraph is returned when a graph export cannot be read.
+var ErrInvalidGraph = errors.New("invalid graph")
+
+const (
+	// EdgeParameter marks a dependency declared as a parameter of a resolver function.
+	EdgeParameter = "parameter"
+	// EdgeField marks a dependency declared by a `container` tag on a field of the resolved struct.
+	EdgeField = "field"
+)
+
+// Graph is the dependency graph of the bindings visible from a container.
+type Graph struct {
+	Version int         `json:"version"`
+	Nodes   []GraphNode `json:"nodes"`
+	Edges   []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a binding, or a dependency that has no binding, within a Graph.
+type GraphNode struct {
+	// ID identifies the node by its type, qualified with the import paths of the packages, and its name.
+	ID string `json:"id"`
+	// Type is the type of the node as displayed by Go, with the package names.
+	Type     string   `json:"type"`
+	Name     string   `json:"name,omitempty"`
+	Lifetime Lifetime `json:"lifetime,omitempty"`
+	// Scope is the depth of the container that owns the binding, 0 being the root container.
+	Scope int `json:"scope"`
+	// Instance is true when the binding was registered with an instance instead of a resolver.
+	Instance bool `json:"instance,omitempty"`
+	// Missing is true when a dependency refers to a type and name that has no binding.
+	Missing bool `json:"missing,omitempty"`
+}
+
+// GraphEdge is a dependency from one node to another.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+	// Field is the name of the struct field declaring the dependency for EdgeField edges.
+	// Fields of nested structs are prefixed with the names of the fields leading to them, separated by dots.
+	Field string `json:"field,omitempty"`
+	// Optional is true when the dependency is declared by an optional field.
+	Optional bool `json:"optional,omitempty"`
+	// Unresolved is true when the target node has no binding.
+	Unresolved bool `json:"unresolved,omitempty"`
+}
+
+// Graph derives the dependency graph of all the bindings visible from the container.
+// Bindings registered in a scope shadow bindings with the same type and name in its parents.
+// Dependencies are taken from the resolver parameter types, the `container` tags of the parameter objects and the
+// `container` tags of the resolved structs. Group fields depend on every binding of their element type.
+func (c *Container) Graph() *Graph {
+	graph := &Graph{Version: GraphVersion, Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	nodes := map[string]*GraphNode{}
+	visible := c.visibleBindings()
+
+	for _, v := range visible {
+		id := graphNodeID(v.t, v.name)
+		nodes[id] = &GraphNode{
+			ID:       id,
+			Type:     v.t.String(),
+			Name:     v.name,
+			Lifetime: v.binding.lifetime,
+			Scope:    v.depth,
+			Instance: v.binding.resolver == nil,
+		}
+	}
+
+	addEdge := func(from string, t reflect.Type, name string, kind string, field string, optional bool) {
+		to := graphNodeID(t, name)
+		if _, exist := nodes[to]; !exist {
+			nodes[to] = &GraphNode{ID: to, Type: t.String(), Name: name, Missing: true}
+		}
+
+		graph.Edges = append(graph.Edges, GraphEdge{
+			From:       from,
+			To:         to,
+			Kind:       kind,
+			Field:      field,
+			Optional:   optional,
+			Unresolved: nodes[to].Missing && !optional,
+		})
+	}
+
+	var addFieldEdges func(from string, structType reflect.Type, prefix string, path map[reflect.Type]bool)
+	addFieldEdges = func(from string, structType reflect.Type, prefix string, path map[reflect.Type]bool) {
+		if path[structType] {
+			return
+		}
+
+		path[structType] = true
+		defer delete(path, structType)
+
+		for i := 0; i < structType.NumField(); i++ {
+			field := structType.Field(i)
+
+			tag, tagged, err := parseFieldTag(field)
+			if tagged && (err != nil || tag.skip) {
+				continue
+			}
+
+			if isNested(field, tag, tagged) {
+				addFieldEdges(from, nestedStruct(field.Type), prefix+field.Name+".", path)
+				continue
+			}
+
+			if !tagged {
+				continue
+			}
+
+			dependency := tag.dependency(field.Type)
+			if !tag.group {
+				addEdge(from, dependency, tag.name, EdgeField, prefix+field.Name, tag.optional)
+				continue
+			}
+
+			for _, other := range visible {
+				if other.t == dependency {
+					addEdge(from, dependency, other.name, EdgeField, prefix+field.Name, false)
+				}
+			}
+		}
+	}
+
+	contextType := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	for _, v := range visible {
+		id := graphNodeID(v.t, v.name)
+
+		if v.binding.resolver != nil {
+			resolverType := reflect.TypeOf(v.binding.resolver)
+			for i := 0; i < resolverType.NumIn(); i++ {
+				if resolverType.In(i).Implements(contextType) || resolverType.In(i) == containerType {
+					continue
+				}
+
+				if isParameterObject(resolverType.In(i)) {
+					addFieldEdges(id, resolverType.In(i), "", map[reflect.Type]bool{})
+					continue
+				}
+
+				addEdge(id, resolverType.In(i), "", EdgeParameter, "", false)
+			}
+		}
+
+		structType := v.t
+		if v.binding.structType != nil {
+			structType = v.binding.structType
+		}
+
+		if structType.Kind() == reflect.Ptr {
+			structType = structType.Elem()
+		}
+
+		if structType.Kind() == reflect.Struct {
+			addFieldEdges(id, structType, "", map[reflect.Type]bool{})
+		}
+	}
+
+	for _, node := range nodes {
+		graph.Nodes = append(graph.Nodes, *node)
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].ID < graph.Nodes[j].ID
+	})
+
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Field < b.Field
+	})
+
+	return graph
+}
+
+// WriteJSON writes the graph as indented JSON using the schema identified by GraphVersion.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(g)
+}
+
+// ReadGraph reads a graph previously written by Graph.WriteJSON.
+func ReadGraph(r io.Reader) (*Graph, error) {
+	var graph Graph
+	if err := json.NewDecoder(r).Decode(&graph); err != nil {
+		return nil, fmt.Errorf("%w, %w", ErrInvalidGraph, err)
+	}
+
+	if graph.Version != GraphVersion {
+		return nil, fmt.Errorf("%w, unsupported version %d", ErrInvalidGraph, graph.Version)
+	}
+
+	return &graph, nil
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language.
+// Missing bindings and unresolved edges are drawn dashed in red.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var sb strings.Builder
+
+	sb.WriteString("digraph container {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+
+	for _, node := range g.Nodes {
+		label := strings.Join(graphNodeLines(node), "\\n")
+		if node.Missing {
+			fmt.Fprintf(&sb, "  %s [label=%s, style=dashed, color=red];\n", dotQuote(node.ID), dotQuoteLabel(label))
+		} else {
+			fmt.Fprintf(&sb, "  %s [label=%s];\n", dotQuote(node.ID), dotQuoteLabel(label))
+		}
+	}
+
+	for _, edge := range g.Edges {
+		attributes := []string{}
+		if edge.Field != "" {
+			attributes = append(attributes, "label="+dotQuote(edge.Field))
+		}
+		if edge.Unresolved {
+			attributes = append(attributes, "style=dashed", "color=red")
+		}
+
+		if len(attributes) == 0 {
+			fmt.Fprintf(&sb, "  %s -> %s;\n", dotQuote(edge.From), dotQuote(edge.To))
+		} else {
+			fmt.Fprintf(&sb, "  %s -> %s [%s];\n", dotQuote(edge.From), dotQuote(edge.To), strings.Join(attributes, ", "))
+		}
+	}
+
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart.
+// Missing bindings use the `missing` class and unresolved edges are drawn dotted in red.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	var sb strings.Builder
+
+	sb.WriteString("graph LR\n")
+
+	ids := make(map[string]string, len(g.Nodes))
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+
+		label := strings.Join(graphNodeLines(node), "<br/>")
+		fmt.Fprintf(&sb, "  %s[\"%s\"]", ids[node.ID], mermaidEscape(label))
+		if node.Missing {
+			sb.WriteString(":::missing")
+		}
+		sb.WriteString("\n")
+	}
+
+	unresolved := []string{}
+	for i, edge := range g.Edges {
+		arrow := "-->"
+		if edge.Unresolved {
+			arrow = "-.->"
+			unresolved = append(unresolved, fmt.Sprint(i))
+		}
+
+		if edge.Field != "" {
+			fmt.Fprintf(&sb, "  %s %s|%s| %s\n", ids[edge.From], arrow, mermaidEscape(edge.Field), ids[edge.To])
+		} else {
+			fmt.Fprintf(&sb, "  %s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+		}
+	}
+
+	sb.WriteString("  classDef missing stroke:#d00,stroke-dasharray:4\n")
+	if len(unresolved) > 0 {
+		fmt.Fprintf(&sb, "  linkStyle %s stroke:#d00\n", strings.Join(unresolved, ","))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// graphNodeID returns the stable identifier of the node for the type and name.
+// Types are identified by their import path so types with the same name in different packages have distinct nodes.
+func graphNodeID(t reflect.Type, name string) string {
+	if name == "" {
+		return qualifiedTypeName(t)
+	}
+
+	return qualifiedTypeName(t) + "#" + name
+}
+
+// qualifiedTypeName returns the name of the type like reflect.Type.String, with the import paths of the named types
+// instead of their package names.
+func qualifiedTypeName(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+
+		return t.PkgPath() + "." + t.Name()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + qualifiedTypeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + qualifiedTypeName(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), qualifiedTypeName(t.Elem()))
+	case reflect.Map:
+		return "map[" + qualifiedTypeName(t.Key()) + "]" + qualifiedTypeName(t.Elem())
+	case reflect.Chan:
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + qualifiedTypeName(t.Elem())
+		case reflect.SendDir:
+			return "chan<- " + qualifiedTypeName(t.Elem())
+		default:
+			return "chan " + qualifiedTypeName(t.Elem())
+		}
+	default:
+		return t.String()
+	}
+}
+
+// graphNodeLines returns the lines describing the node in rendered graphs.
+func graphNodeLines(node GraphNode) []string {
+	lines := []string{node.Type}
+	if node.Name != "" {
+		lines = append(lines, "name: "+node.Name)
+	}
+
+	if node.Missing {
+		return append(lines, "missing")
+	}
+
+	lifetime := string(node.Lifetime)
+	if node.Instance {
+		lifetime += ", instance"
+	}
+	lines = append(lines, lifetime)
+
+	if node.Scope > 0 {
+		lines = append(lines, fmt.Sprintf("scope %d", node.Scope))
+	}
+
+	return lines
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// dotQuoteLabel quotes a label whose lines are already separated with `\n` escapes.
+func dotQuoteLabel(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<br/>", "<br/>", "<", "#lt;", ">", "#gt;", "|", "#124;").Replace(s)
+}
@@ -0,0 +1,236 @@
+package container_test
+
+import (
+	"bytes"
+	"encoding/json"
+	htmltemplate "html/template"
+	"testing"
+	texttemplate "text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+type Report struct {
+	Shape    Shape    `container:"type"`
+	Database Database `container:"name"`
+}
+
+// testPackage is the import path of the types of the tests, which identifies them in graphs and metrics.
+const testPackage = "github.com/wbreza/container/v4_test"
+
+func findNode(graph *container.Graph, id string) *container.GraphNode {
+	for i := range graph.Nodes {
+		if graph.Nodes[i].ID == id {
+			return &graph.Nodes[i]
+		}
+	}
+
+	return nil
+}
+
+func TestGraph_Parameter_Edges(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 5}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterTransient(func(s Shape, options *DatabaseOptions) Database {
+		return &MySQL{options: options}
+	})
+	assert.NoError(t, err)
+
+	graph := c.Graph()
+	assert.Equal(t, container.GraphVersion, graph.Version)
+
+	db := findNode(graph, testPackage+".Database")
+	assert.NotNil(t, db)
+	assert.Equal(t, container.Transient, db.Lifetime)
+
+	missing := findNode(graph, "*"+testPackage+".DatabaseOptions")
+	assert.NotNil(t, missing)
+	assert.True(t, missing.Missing)
+
+	assert.Equal(t, []container.GraphEdge{
+		{From: testPackage + ".Database", To: "*" + testPackage + ".DatabaseOptions", Kind: container.EdgeParameter, Unresolved: true},
+		{From: testPackage + ".Database", To: testPackage + ".Shape", Kind: container.EdgeParameter},
+	}, graph.Edges)
+}
+
+func TestGraph_Field_Edges(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterInstance(&Report{})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedSingleton("Database", func() Database {
+		return &MySQL{}
+	})
+	assert.NoError(t, err)
+
+	graph := c.Graph()
+
+	report := findNode(graph, "*"+testPackage+".Report")
+	assert.NotNil(t, report)
+	assert.True(t, report.Instance)
+
+	assert.Equal(t, []container.GraphEdge{
+		{From: "*" + testPackage + ".Report", To: testPackage + ".Database#Database", Kind: container.EdgeField, Field: "Database"},
+		{From: "*" + testPackage + ".Report", To: testPackage + ".Shape", Kind: container.EdgeField, Field: "Shape", Unresolved: true},
+	}, graph.Edges)
+}
+
+func TestGraph_Scope_Ownership(t *testing.T) {
+	root := container.New()
+
+	err := root.RegisterSingleton(func() Shape {
+		return &Circle{}
+	})
+	assert.NoError(t, err)
+
+	err = root.RegisterScoped(func(s Shape) Database {
+		return &MySQL{}
+	})
+	assert.NoError(t, err)
+
+	scope, err := root.NewScope()
+	assert.NoError(t, err)
+
+	err = scope.RegisterInstance([]string{"arg"})
+	assert.NoError(t, err)
+
+	graph := scope.Graph()
+
+	assert.Equal(t, 0, findNode(graph, testPackage+".Shape").Scope)
+	assert.Equal(t, 1, findNode(graph, testPackage+".Database").Scope)
+	assert.Equal(t, 1, findNode(graph, "[]string").Scope)
+
+	// The root container does not see bindings registered in the scope.
+	assert.Nil(t, findNode(root.Graph(), "[]string"))
+}
+
+func TestGraph_WriteJSON_Round_Trip(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func(db Database) Shape {
+		return &Circle{}
+	})
+	assert.NoError(t, err)
+
+	graph := c.Graph()
+
+	var buf bytes.Buffer
+	err = graph.WriteJSON(&buf)
+	assert.NoError(t, err)
+
+	var decoded container.Graph
+	err = json.Unmarshal(buf.Bytes(), &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, *graph, decoded)
+}
+
+func TestGraph_WriteDOT(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterNamedSingleton("round", func(db Database) Shape {
+		return &Circle{}
+	})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = c.Graph().WriteDOT(&buf)
+	assert.NoError(t, err)
+
+	expected := `digraph container {
+  rankdir=LR;
+  node [shape=box];
+  "github.com/wbreza/container/v4_test.Database" [label="container_test.Database\nmissing", style=dashed, color=red];
+  "github.com/wbreza/container/v4_test.Shape#round" [label="container_test.Shape\nname: round\nsingleton"];
+  "github.com/wbreza/container/v4_test.Shape#round" -> "github.com/wbreza/container/v4_test.Database" [style=dashed, color=red];
+}
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestGraph_WriteMermaid(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Database {
+		return &MySQL{}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterTransient(func(db Database, options *DatabaseOptions) Shape {
+		return &Circle{}
+	})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = c.Graph().WriteMermaid(&buf)
+	assert.NoError(t, err)
+
+	expected := `graph LR
+  n0["*container_test.DatabaseOptions<br/>missing"]:::missing
+  n1["container_test.Database<br/>singleton"]
+  n2["container_test.Shape<br/>transient"]
+  n2 -.-> n0
+  n2 --> n1
+  classDef missing stroke:#d00,stroke-dasharray:4
+  linkStyle 0 stroke:#d00
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestGraph_ReadGraph(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func(db Database) Shape {
+		return &Circle{}
+	})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = c.Graph().WriteJSON(&buf)
+	assert.NoError(t, err)
+
+	graph, err := container.ReadGraph(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, c.Graph(), graph)
+}
+
+func TestGraph_ReadGraph_With_Unsupported_Version_It_Should_Fail(t *testing.T) {
+	_, err := container.ReadGraph(bytes.NewBufferString(`{"version": 99, "nodes": [], "edges": []}`))
+	assert.ErrorIs(t, err, container.ErrInvalidGraph)
+
+	_, err = container.ReadGraph(bytes.NewBufferString(`not json`))
+	assert.ErrorIs(t, err, container.ErrInvalidGraph)
+}
+
+func TestGraph_Types_With_The_Same_Name_In_Different_Packages(t *testing.T) {
+	c := container.New()
+
+	container.MustRegisterSingleton(c, func() *texttemplate.Template {
+		return texttemplate.New("text")
+	})
+	container.MustRegisterSingleton(c, func(html *htmltemplate.Template) Shape {
+		return &Circle{}
+	})
+	container.MustRegisterSingleton(c, func() *htmltemplate.Template {
+		return htmltemplate.New("html")
+	})
+
+	graph := c.Graph()
+
+	text := findNode(graph, "*text/template.Template")
+	html := findNode(graph, "*html/template.Template")
+	assert.NotNil(t, text)
+	assert.NotNil(t, html)
+	assert.Equal(t, "*template.Template", text.Type)
+	assert.Equal(t, "*template.Template", html.Type)
+
+	assert.Equal(t, []container.GraphEdge{
+		{From: testPackage + ".Shape", To: "*html/template.Template", Kind: container.EdgeParameter},
+	}, graph.Edges)
+}
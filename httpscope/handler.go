@@ -0,0 +1,93 @@
+package httpscope
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/wbreza/container/v4"
+)
+
+// ErrScopeNotFound is reported when a handler serves a request that was not served by the middleware.
+var ErrScopeNotFound = errors.New("request scope not found")
+
+// HandlerOption configures a handler returned by Handle.
+type HandlerOption func(*handler)
+
+// ErrorHandler writes the response of a request whose handler could not be called or returned an error.
+// Failures to resolve the parameters wrap container.ErrResolutionFailed.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// WithErrorHandler replaces DefaultErrorHandler for the handler.
+func WithErrorHandler(errorHandler ErrorHandler) HandlerOption {
+	return func(h *handler) {
+		h.errorHandler = errorHandler
+	}
+}
+
+// DefaultErrorHandler responds with an internal server error without disclosing the error.
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+var (
+	responseWriterType = reflect.TypeOf((*http.ResponseWriter)(nil)).Elem()
+	requestType        = reflect.TypeOf((*http.Request)(nil))
+)
+
+type handler struct {
+	function     interface{}
+	writer       bool // writer is true when the function has a http.ResponseWriter parameter.
+	request      bool // request is true when the function has a *http.Request parameter.
+	errorHandler ErrorHandler
+}
+
+// Handle returns a handler calling the function, typically
+//
+//	func(w http.ResponseWriter, r *http.Request, users UserService, logger Logger) error
+//
+// The http.ResponseWriter and *http.Request parameters receive the writer and the request served by the handler, the
+// other parameters are resolved from the request scope like container.Call does.
+// The function must return nothing or a single error, Handle panics otherwise.
+// The request must be served by the middleware. When it was not, when a parameter cannot be resolved or when the
+// function returns an error, the error handler writes the response.
+func Handle(function interface{}, options ...HandlerOption) http.Handler {
+	functionType := reflect.TypeOf(function)
+	if functionType == nil || functionType.Kind() != reflect.Func || functionType.NumOut() > 1 ||
+		(functionType.NumOut() == 1 && functionType.Out(0) != reflect.TypeOf((*error)(nil)).Elem()) {
+		panic(fmt.Errorf("%w, the handler must be a function returning nothing or an error", container.ErrInvalidReceiver))
+	}
+
+	h := &handler{function: function, errorHandler: DefaultErrorHandler}
+	for i := 0; i < functionType.NumIn(); i++ {
+		h.writer = h.writer || functionType.In(i) == responseWriterType
+		h.request = h.request || functionType.In(i) == requestType
+	}
+
+	for _, option := range options {
+		option(h)
+	}
+
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	scope, ok := Scope(r)
+	if !ok {
+		h.errorHandler(w, r, ErrScopeNotFound)
+		return
+	}
+
+	arguments := []container.Argument{}
+	if h.writer {
+		arguments = append(arguments, container.ArgOf(w))
+	}
+	if h.request {
+		arguments = append(arguments, container.ArgOf(r))
+	}
+
+	if _, err := scope.CallWithResults(r.Context(), h.function, arguments...); err != nil {
+		h.errorHandler(w, r, err)
+	}
+}
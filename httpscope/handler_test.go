@@ -0,0 +1,152 @@
+package httpscope_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+	"github.com/wbreza/container/v4/httpscope"
+)
+
+type UserService interface {
+	Name(id string) string
+}
+
+type users struct{}
+
+func (u *users) Name(id string) string {
+	return "user " + id
+}
+
+type recorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func TestHandle_With_Injected_Parameters(t *testing.T) {
+	sessions := []*Session{}
+	c := container.New(container.WithDisposal())
+	err := c.RegisterScoped(func(r *http.Request) *Session {
+		session := &Session{ID: len(sessions) + 1}
+		if r.URL.Query().Get("fail") != "" {
+			session.err = errors.New("close failed")
+		}
+		sessions = append(sessions, session)
+
+		return session
+	})
+	assert.NoError(t, err)
+
+	container.MustRegisterSingleton(c, func() UserService {
+		return &users{}
+	})
+
+	handler := httpscope.Handle(func(w http.ResponseWriter, r *http.Request, service UserService, session *Session) error {
+		_, ok := w.(*recorder)
+		assert.True(t, ok)
+
+		fmt.Fprintf(w, "%s in session %d", service.Name(r.URL.Query().Get("id")), session.ID)
+		return nil
+	})
+
+	// The writer wrapped after the middleware is the one passed to the handler.
+	wrapped := httpscope.Middleware(c)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(&recorder{ResponseWriter: w}, r)
+	}))
+
+	response := serve(wrapped, "/?id=7")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "user 7 in session 1", response.Body.String())
+}
+
+func TestHandle_With_Error_Handler(t *testing.T) {
+	sessions := []*Session{}
+	c := container.New(container.WithDisposal())
+	err := c.RegisterScoped(func(r *http.Request) *Session {
+		session := &Session{ID: len(sessions) + 1}
+		if r.URL.Query().Get("fail") != "" {
+			session.err = errors.New("close failed")
+		}
+		sessions = append(sessions, session)
+
+		return session
+	})
+	assert.NoError(t, err)
+
+	expected := errors.New("forbidden")
+
+	errorHandler := httpscope.WithErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		switch {
+		case errors.Is(err, container.ErrResolutionFailed):
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		case errors.Is(err, httpscope.ErrScopeNotFound):
+			http.Error(w, "no scope", http.StatusBadGateway)
+		case errors.Is(err, expected):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		}
+	})
+
+	tests := map[string]struct {
+		handler http.Handler
+		status  int
+	}{
+		"resolution failure": {
+			handler: httpscope.Middleware(c)(httpscope.Handle(func(service UserService) {}, errorHandler)),
+			status:  http.StatusServiceUnavailable,
+		},
+		"returned error": {
+			handler: httpscope.Middleware(c)(httpscope.Handle(func(w http.ResponseWriter) error { return expected }, errorHandler)),
+			status:  http.StatusForbidden,
+		},
+		"without middleware": {
+			handler: httpscope.Handle(func() {}, errorHandler),
+			status:  http.StatusBadGateway,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.status, serve(test.handler, "/").Code)
+		})
+	}
+}
+
+func TestHandle_With_Invalid_Function_It_Should_Panic(t *testing.T) {
+	assert.Panics(t, func() {
+		httpscope.Handle(func() int { return 0 })
+	})
+	assert.Panics(t, func() {
+		httpscope.Handle("not a function")
+	})
+}
+
+func TestHandle_Without_Writer_Or_Request_Parameters(t *testing.T) {
+	sessions := []*Session{}
+	c := container.New(container.WithDisposal())
+	err := c.RegisterScoped(func(r *http.Request) *Session {
+		session := &Session{ID: len(sessions) + 1}
+		if r.URL.Query().Get("fail") != "" {
+			session.err = errors.New("close failed")
+		}
+		sessions = append(sessions, session)
+
+		return session
+	})
+	assert.NoError(t, err)
+
+	handler := httpscope.Middleware(c)(httpscope.Handle(func(session *Session) {}))
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Len(t, sessions, 1)
+}
@@ -0,0 +1,84 @@
+// Package httpscope creates a scope of a container for every HTTP request.
+//
+// Wrap the handlers with the middleware and write them as functions resolving their dependencies from the scope:
+//
+//	mux.Handle("/users", httpscope.Handle(func(w http.ResponseWriter, r *http.Request, users UserService) {
+//		...
+//	}))
+//	http.ListenAndServe(":8080", httpscope.Middleware(c)(mux))
+//
+// Scoped bindings are resolved once per request. For containers created with container.WithDisposal, the concretes
+// constructed for the request that implement io.Closer are closed once it is served.
+package httpscope
+
+import (
+	"net/http"
+
+	"github.com/wbreza/container/v4"
+)
+
+// Option configures the middleware.
+type Option func(*middleware)
+
+// OnCloseError sets the function called with the error returned when closing a request scope.
+// The errors are ignored by default since the response is already written.
+func OnCloseError(handler func(r *http.Request, err error)) Option {
+	return func(m *middleware) {
+		m.onCloseError = handler
+	}
+}
+
+type middleware struct {
+	container    *container.Container
+	next         http.Handler
+	onCloseError func(r *http.Request, err error)
+}
+
+// Middleware returns a middleware creating a scope of the container for every request.
+// The *http.Request and the http.ResponseWriter are registered in the scope as scoped bindings, the scope is carried by
+// the request context, see Scope and container.FromContext, and the scope is closed when the next handler returns.
+func Middleware(c *container.Container, options ...Option) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		m := &middleware{container: c, next: next}
+		for _, option := range options {
+			option(m)
+		}
+
+		return m
+	}
+}
+
+func (m *middleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The request is bound before the scope exists, the resolver reads it once the context carries the scope.
+	var request *http.Request
+
+	scope, err := m.container.NewScope(
+		container.WithRegistration(container.RegisterOptions{
+			Resolver: func() *http.Request { return request },
+			Lifetime: container.Scoped,
+		}),
+		container.WithRegistration(container.RegisterOptions{
+			Resolver: func() http.ResponseWriter { return w },
+			Lifetime: container.Scoped,
+		}),
+	)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	defer func() {
+		if err := scope.Close(); err != nil && m.onCloseError != nil {
+			m.onCloseError(request, err)
+		}
+	}()
+
+	request = r.WithContext(container.WithContainer(r.Context(), scope))
+
+	m.next.ServeHTTP(w, request)
+}
+
+// Scope returns the scope created for the request by the middleware.
+func Scope(r *http.Request) (*container.Container, bool) {
+	return container.FromContext(r.Context())
+}
@@ -0,0 +1,158 @@
+package httpscope_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+	"github.com/wbreza/container/v4/httpscope"
+)
+
+type Session struct {
+	ID     int
+	closed bool
+	err    error
+}
+
+func (s *Session) Close() error {
+	s.closed = true
+	return s.err
+}
+
+func serve(handler http.Handler, target string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+
+	return recorder
+}
+
+func TestMiddleware_Creates_A_Scope_Per_Request(t *testing.T) {
+	sessions := []*Session{}
+	c := container.New(container.WithDisposal())
+	err := c.RegisterScoped(func(r *http.Request) *Session {
+		session := &Session{ID: len(sessions) + 1}
+		if r.URL.Query().Get("fail") != "" {
+			session.err = errors.New("close failed")
+		}
+		sessions = append(sessions, session)
+
+		return session
+	})
+	assert.NoError(t, err)
+
+	handler := httpscope.Middleware(c)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope, ok := httpscope.Scope(r)
+		assert.True(t, ok)
+		assert.Equal(t, 1, c.ActiveScopes())
+
+		var request *http.Request
+		assert.NoError(t, scope.Resolve(r.Context(), &request))
+		assert.Same(t, r, request)
+
+		var writer http.ResponseWriter
+		assert.NoError(t, scope.Resolve(r.Context(), &writer))
+		assert.Same(t, w, writer)
+
+		var first, second *Session
+		assert.NoError(t, scope.Resolve(r.Context(), &first))
+		assert.NoError(t, scope.Resolve(r.Context(), &second))
+		assert.Same(t, first, second)
+
+		fmt.Fprint(w, first.ID)
+	}))
+
+	assert.Equal(t, "1", serve(handler, "/").Body.String())
+	assert.Equal(t, "2", serve(handler, "/").Body.String())
+
+	assert.Len(t, sessions, 2)
+	assert.True(t, sessions[0].closed)
+	assert.True(t, sessions[1].closed)
+	assert.Equal(t, 0, c.ActiveScopes())
+}
+
+func TestMiddleware_Reports_Close_Errors(t *testing.T) {
+	sessions := []*Session{}
+	c := container.New(container.WithDisposal())
+	err := c.RegisterScoped(func(r *http.Request) *Session {
+		session := &Session{ID: len(sessions) + 1}
+		if r.URL.Query().Get("fail") != "" {
+			session.err = errors.New("close failed")
+		}
+		sessions = append(sessions, session)
+
+		return session
+	})
+	assert.NoError(t, err)
+
+	var reported error
+
+	handler := httpscope.Middleware(c, httpscope.OnCloseError(func(r *http.Request, err error) {
+		reported = err
+	}))(httpscope.Handle(func(session *Session) {}))
+
+	serve(handler, "/")
+	assert.NoError(t, reported)
+
+	serve(handler, "/?fail=true")
+	assert.EqualError(t, reported, "close failed")
+}
+
+func TestHandle(t *testing.T) {
+	sessions := []*Session{}
+	c := container.New(container.WithDisposal())
+	err := c.RegisterScoped(func(r *http.Request) *Session {
+		session := &Session{ID: len(sessions) + 1}
+		if r.URL.Query().Get("fail") != "" {
+			session.err = errors.New("close failed")
+		}
+		sessions = append(sessions, session)
+
+		return session
+	})
+	assert.NoError(t, err)
+
+	handler := httpscope.Middleware(c)(httpscope.Handle(func(ctx context.Context, w http.ResponseWriter, session *Session) {
+		_, ok := container.FromContext(ctx)
+		assert.True(t, ok)
+
+		fmt.Fprint(w, session.ID)
+	}))
+
+	recorder := serve(handler, "/")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "1", recorder.Body.String())
+}
+
+func TestHandle_With_Failure_It_Should_Respond_With_An_Error(t *testing.T) {
+	sessions := []*Session{}
+	c := container.New(container.WithDisposal())
+	err := c.RegisterScoped(func(r *http.Request) *Session {
+		session := &Session{ID: len(sessions) + 1}
+		if r.URL.Query().Get("fail") != "" {
+			session.err = errors.New("close failed")
+		}
+		sessions = append(sessions, session)
+
+		return session
+	})
+	assert.NoError(t, err)
+
+	tests := map[string]http.Handler{
+		"missing binding": httpscope.Middleware(c)(httpscope.Handle(func(db interface{ Ping() }) {})),
+		"returned error": httpscope.Middleware(c)(httpscope.Handle(func() error {
+			return errors.New("failed")
+		})),
+		"without middleware": httpscope.Handle(func() {}),
+	}
+
+	for name, handler := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, http.StatusInternalServerError, serve(handler, "/").Code)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WithMethodInjection enables setter injection by convention for the container and the scopes created from it.
+// Once a resolver constructs a concrete, the container calls every exported method of the concrete whose name starts
+// with the prefix, in name order, resolving their parameters like Call does. The methods must return nothing or a
+// single error. They are called before Init, and never on cached concretes or instances registered with
+// RegisterInstance.
+//
+//	container.New(container.WithMethodInjection("Inject"))
+func WithMethodInjection(prefix string) Option {
+	return func(c *Container) {
+		c.injectPrefix = prefix
+	}
+}
+
+// injectMethods calls the methods matching the injection prefix of the container followed by the named methods on the
+// constructed concrete. A method matching both is only called once.
+func (c *Container) injectMethods(ctx context.Context, concrete interface{}, names []string) error {
+	if isNil(concrete) || (c.injectPrefix == "" && len(names) == 0) {
+		return nil
+	}
+
+	value := reflect.ValueOf(concrete)
+	called := map[string]bool{}
+
+	if c.injectPrefix != "" {
+		for i := 0; i < value.NumMethod(); i++ {
+			name := value.Type().Method(i).Name
+			if !strings.HasPrefix(name, c.injectPrefix) {
+				continue
+			}
+
+			called[name] = true
+			if err := c.injectMethod(ctx, value, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range names {
+		if called[name] {
+			continue
+		}
+
+		called[name] = true
+		if err := c.injectMethod(ctx, value, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// injectMethod calls the named method of the concrete with resolved parameters.
+func (c *Container) injectMethod(ctx context.Context, value reflect.Value, name string) error {
+	method := value.MethodByName(name)
+	if !method.IsValid() {
+		return fmt.Errorf("%w, type '%s' has no method '%s'", ErrInvalidReceiver, value.Type().String(), name)
+	}
+
+	if methodType := method.Type(); methodType.NumOut() > 1 || (methodType.NumOut() == 1 && methodType.Out(0) != errorType) {
+		return fmt.Errorf("%w, method '%s' of type '%s' must return nothing or an error", ErrInvalidReceiver, name, value.Type().String())
+	}
+
+	if err := c.Call(ctx, method.Interface()); err != nil {
+		return fmt.Errorf("%w for method '%s' of type '%s', Error: %w", ErrResolutionFailed, name, value.Type().String(), err)
+	}
+
+	return nil
+}
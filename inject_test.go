@@ -0,0 +1,152 @@
+package container_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+type Mailer struct {
+	db     Database
+	shape  Shape
+	calls  []string
+	inited bool
+}
+
+func (m *Mailer) InjectDatabase(db Database) {
+	m.calls = append(m.calls, "InjectDatabase")
+	m.db = db
+}
+
+func (m *Mailer) InjectShape(ctx context.Context, shape Shape) error {
+	m.calls = append(m.calls, "InjectShape")
+	m.shape = shape
+	return nil
+}
+
+func (m *Mailer) SetShape(shape Shape) {
+	m.calls = append(m.calls, "SetShape")
+	m.shape = shape
+}
+
+func (m *Mailer) Init(ctx context.Context) error {
+	m.calls = append(m.calls, "Init")
+	m.inited = m.db != nil
+	return nil
+}
+
+func TestContainer_Resolve_With_Method_Injection(t *testing.T) {
+	c := container.New(container.WithMethodInjection("Inject"))
+	container.MustRegisterSingleton(c, func() Database {
+		return &MySQL{}
+	})
+	container.MustRegisterSingleton(c, func() Shape {
+		return &Circle{a: 1}
+	})
+	container.MustRegisterSingleton(c, func() *Mailer {
+		return &Mailer{}
+	})
+
+	var mailer *Mailer
+	assert.NoError(t, c.Resolve(context.Background(), &mailer))
+	assert.Equal(t, []string{"InjectDatabase", "InjectShape", "Init"}, mailer.calls)
+	assert.NotNil(t, mailer.db)
+	assert.NotNil(t, mailer.shape)
+	assert.True(t, mailer.inited)
+
+	// Cached concretes are not injected again.
+	assert.NoError(t, c.Resolve(context.Background(), &mailer))
+	assert.Len(t, mailer.calls, 3)
+}
+
+func TestContainer_Resolve_With_Method_Injection_Inherited_By_Scopes(t *testing.T) {
+	c := container.New(container.WithMethodInjection("Inject"))
+	container.MustRegisterScoped(c, func() Database {
+		return &MySQL{}
+	})
+	container.MustRegisterScoped(c, func() Shape {
+		return &Circle{a: 1}
+	})
+	container.MustRegisterScoped(c, func() *Mailer {
+		return &Mailer{}
+	})
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	var mailer *Mailer
+	assert.NoError(t, scope.Resolve(context.Background(), &mailer))
+	assert.NotNil(t, mailer.db)
+}
+
+func TestContainer_Resolve_With_Explicit_Inject_Methods(t *testing.T) {
+	c := container.New()
+	container.MustRegisterSingleton(c, func() Shape {
+		return &Circle{a: 1}
+	})
+
+	err := c.Register(container.RegisterOptions{
+		Resolver:      func() *Mailer { return &Mailer{} },
+		Lifetime:      container.Transient,
+		InjectMethods: []string{"SetShape"},
+	})
+	assert.NoError(t, err)
+
+	var mailer *Mailer
+	assert.NoError(t, c.Resolve(context.Background(), &mailer))
+	assert.Equal(t, []string{"SetShape", "Init"}, mailer.calls)
+	assert.NotNil(t, mailer.shape)
+	assert.Nil(t, mailer.db)
+}
+
+func TestContainer_Resolve_Without_Method_Injection(t *testing.T) {
+	c := container.New()
+	container.MustRegisterTransient(c, func() *Mailer {
+		return &Mailer{}
+	})
+
+	var mailer *Mailer
+	assert.NoError(t, c.Resolve(context.Background(), &mailer))
+	assert.Equal(t, []string{"Init"}, mailer.calls)
+}
+
+func TestContainer_Resolve_With_Method_Injection_Failure_It_Should_Fail(t *testing.T) {
+	c := container.New(container.WithMethodInjection("Inject"))
+	container.MustRegisterTransient(c, func() *Mailer {
+		return &Mailer{}
+	})
+
+	var mailer *Mailer
+	err := c.Resolve(context.Background(), &mailer)
+	assert.ErrorIs(t, err, container.ErrResolutionFailed)
+	assert.ErrorIs(t, err, container.ErrBindingNotFound)
+
+	err = c.Register(container.RegisterOptions{
+		Resolver:      func() Shape { return &Circle{} },
+		InjectMethods: []string{"Missing"},
+	})
+	assert.NoError(t, err)
+
+	var shape Shape
+	err = c.Resolve(context.Background(), &shape)
+	assert.ErrorIs(t, err, container.ErrInvalidReceiver)
+}
+
+type BrokenSetter struct{}
+
+func (b *BrokenSetter) InjectNothing() int {
+	return 1
+}
+
+func TestContainer_Resolve_With_Invalid_Inject_Method_It_Should_Fail(t *testing.T) {
+	c := container.New(container.WithMethodInjection("Inject"))
+	container.MustRegisterTransient(c, func() *BrokenSetter {
+		return &BrokenSetter{}
+	})
+
+	var setter *BrokenSetter
+	err := c.Resolve(context.Background(), &setter)
+	assert.ErrorIs(t, err, container.ErrInvalidReceiver)
+}
@@ -0,0 +1,174 @@
+// Package inspect serves the state of a running container over HTTP.
+//
+// Mount the handler on a debug server, stripping the prefix it is mounted on:
+//
+//	mux.Handle("/debug/container/", http.StripPrefix("/debug/container", inspect.NewHandler(c)))
+//
+// The following pages are served:
+//
+//	/               overview of the registrations and scopes as HTML, or JSON with ?format=json
+//	/registrations  registrations as JSON, including instantiation state and construction timings
+//	/graph          dependency graph as JSON, or DOT and Mermaid with ?format=dot and ?format=mermaid
+//	/scopes         active scope count as JSON
+package inspect
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/wbreza/container/v4"
+)
+
+// Snapshot is the state of a container at the time it was inspected.
+type Snapshot struct {
+	Registrations []container.Registration `json:"registrations"`
+	ActiveScopes  int                      `json:"activeScopes"`
+}
+
+// Scopes is the scope information of a container at the time it was inspected.
+type Scopes struct {
+	Active int `json:"active"`
+}
+
+type handler struct {
+	container *container.Container
+	mux       *http.ServeMux
+}
+
+// NewHandler returns a handler serving the state of the container.
+// Only GET and HEAD requests are accepted.
+func NewHandler(c *container.Container) http.Handler {
+	h := &handler{container: c, mux: http.NewServeMux()}
+
+	h.mux.HandleFunc("/", h.serveOverview)
+	h.mux.HandleFunc("/registrations", h.serveRegistrations)
+	h.mux.HandleFunc("/graph", h.serveGraph)
+	h.mux.HandleFunc("/scopes", h.serveScopes)
+
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *handler) snapshot() Snapshot {
+	return Snapshot{
+		Registrations: h.container.Registrations(),
+		ActiveScopes:  h.container.ActiveScopes(),
+	}
+}
+
+func (h *handler) serveOverview(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" && r.URL.Path != "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, h.snapshot())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := overviewTemplate.Execute(w, h.snapshot()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *handler) serveRegistrations(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.container.Registrations())
+}
+
+func (h *handler) serveGraph(w http.ResponseWriter, r *http.Request) {
+	graph := h.container.Graph()
+
+	var err error
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		err = graph.WriteJSON(w)
+	case "dot":
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		err = graph.WriteDOT(w)
+	case "mermaid":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		err = graph.WriteMermaid(w)
+	default:
+		http.Error(w, "unknown graph format, expected json, dot or mermaid", http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *handler) serveScopes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, Scopes{Active: h.container.ActiveScopes()})
+}
+
+// wantsJSON reports whether the client asked for JSON instead of HTML.
+func wantsJSON(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "json"
+	}
+
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var overviewTemplate = template.Must(template.New("overview").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Container</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; }
+td.number { text-align: right; }
+</style>
+</head>
+<body>
+<h1>Container</h1>
+<p>Active scopes: {{.ActiveScopes}}</p>
+<p>
+<a href="registrations">registrations.json</a> |
+<a href="graph">graph.json</a> |
+<a href="graph?format=dot">graph.dot</a> |
+<a href="graph?format=mermaid">graph.mermaid</a>
+</p>
+<h2>Registrations</h2>
+<table>
+<tr><th>Type</th><th>Name</th><th>Lifetime</th><th>Scope</th><th>Instantiated</th><th>Constructions</th><th>Construction time</th></tr>
+{{range .Registrations}}<tr>
+<td>{{.Type}}</td>
+<td>{{.Name}}</td>
+<td>{{.Lifetime}}{{if .Instance}} (instance){{end}}</td>
+<td class="number">{{.Scope}}</td>
+<td>{{if .Instantiated}}yes{{else}}no{{end}}</td>
+<td class="number">{{.Constructions}}</td>
+<td class="number">{{.ConstructionTime}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
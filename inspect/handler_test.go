@@ -0,0 +1,204 @@
+package inspect_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+	"github.com/wbreza/container/v4/inspect"
+)
+
+type Config struct{}
+
+type Database struct{}
+
+func serve(handler http.Handler, method string, target string, accept string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, nil)
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func TestHandler_Overview_HTML(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+
+	var config *Config
+	assert.NoError(t, c.Resolve(context.Background(), &config))
+
+	_, err := c.NewScope()
+	assert.NoError(t, err)
+
+	handler := inspect.NewHandler(c)
+
+	response := serve(handler, http.MethodGet, "/", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, response.Body.String(), "Active scopes: 1")
+	assert.Contains(t, response.Body.String(), "*inspect_test.Database")
+}
+
+func TestHandler_Overview_JSON(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+
+	var config *Config
+	assert.NoError(t, c.Resolve(context.Background(), &config))
+
+	_, err := c.NewScope()
+	assert.NoError(t, err)
+
+	handler := inspect.NewHandler(c)
+
+	for _, response := range []*httptest.ResponseRecorder{
+		serve(handler, http.MethodGet, "/?format=json", ""),
+		serve(handler, http.MethodGet, "/", "application/json"),
+	} {
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		var snapshot inspect.Snapshot
+		err := json.Unmarshal(response.Body.Bytes(), &snapshot)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 1, snapshot.ActiveScopes)
+		assert.Len(t, snapshot.Registrations, 2)
+		assert.Equal(t, "*inspect_test.Config", snapshot.Registrations[0].Type)
+		assert.True(t, snapshot.Registrations[0].Instantiated)
+		assert.Equal(t, 1, snapshot.Registrations[0].Constructions)
+		assert.Equal(t, "*inspect_test.Database", snapshot.Registrations[1].Type)
+		assert.False(t, snapshot.Registrations[1].Instantiated)
+	}
+}
+
+func TestHandler_Registrations(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+
+	var config *Config
+	assert.NoError(t, c.Resolve(context.Background(), &config))
+
+	_, err := c.NewScope()
+	assert.NoError(t, err)
+
+	handler := inspect.NewHandler(c)
+
+	response := serve(handler, http.MethodGet, "/registrations", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var registrations []container.Registration
+	err = json.Unmarshal(response.Body.Bytes(), &registrations)
+	assert.NoError(t, err)
+	assert.Len(t, registrations, 2)
+}
+
+func TestHandler_Graph(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+
+	var config *Config
+	assert.NoError(t, c.Resolve(context.Background(), &config))
+
+	_, err := c.NewScope()
+	assert.NoError(t, err)
+
+	handler := inspect.NewHandler(c)
+
+	response := serve(handler, http.MethodGet, "/graph", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	graph, err := container.ReadGraph(response.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, c.Graph(), graph)
+
+	response = serve(handler, http.MethodGet, "/graph?format=dot", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.True(t, strings.HasPrefix(response.Body.String(), "digraph container {"))
+
+	response = serve(handler, http.MethodGet, "/graph?format=mermaid", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.True(t, strings.HasPrefix(response.Body.String(), "graph LR"))
+
+	response = serve(handler, http.MethodGet, "/graph?format=svg", "")
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestHandler_Scopes(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+
+	var config *Config
+	assert.NoError(t, c.Resolve(context.Background(), &config))
+
+	_, err := c.NewScope()
+	assert.NoError(t, err)
+
+	handler := inspect.NewHandler(c)
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	response := serve(handler, http.MethodGet, "/scopes", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"active": 2}`, response.Body.String())
+
+	assert.NoError(t, scope.Close())
+
+	response = serve(handler, http.MethodGet, "/scopes", "")
+	assert.JSONEq(t, `{"active": 1}`, response.Body.String())
+}
+
+func TestHandler_Not_Found(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+
+	var config *Config
+	assert.NoError(t, c.Resolve(context.Background(), &config))
+
+	_, err := c.NewScope()
+	assert.NoError(t, err)
+
+	handler := inspect.NewHandler(c)
+
+	response := serve(handler, http.MethodGet, "/missing", "")
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestHandler_Method_Not_Allowed(t *testing.T) {
+	c := container.New()
+
+	assert.NoError(t, c.RegisterSingleton(func() *Config { return &Config{} }))
+	assert.NoError(t, c.RegisterSingleton(func(config *Config) *Database { return &Database{} }))
+
+	var config *Config
+	assert.NoError(t, c.Resolve(context.Background(), &config))
+
+	_, err := c.NewScope()
+	assert.NoError(t, err)
+
+	handler := inspect.NewHandler(c)
+
+	response := serve(handler, http.MethodPost, "/", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, response.Code)
+}
@@ -0,0 +1,123 @@
+package container
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Registration describes a binding visible from a container.
+type Registration struct {
+	Type     string   `json:"type"`
+	Name     string   `json:"name,omitempty"`
+	Lifetime Lifetime `json:"lifetime"`
+	// Scope is the depth of the container that owns the binding, 0 being the root container.
+	Scope int `json:"scope"`
+	// Instance is true when the binding was registered with an instance instead of a resolver.
+	Instance bool `json:"instance,omitempty"`
+	// Instantiated is true when the binding holds a concrete that is returned without calling the resolver.
+	Instantiated bool `json:"instantiated"`
+	// Constructions counts the calls made to the resolver, including the failed ones.
+	Constructions int `json:"constructions"`
+	// ConstructionTime is the total time spent in the resolver.
+	ConstructionTime time.Duration `json:"constructionTime"`
+	// FailurePolicy is the policy the binding was registered with.
+	FailurePolicy FailurePolicy `json:"failurePolicy"`
+	// ScopeKind is the kind of the scopes the binding is restricted to, if any.
+	ScopeKind string `json:"scopeKind,omitempty"`
+	// Eager is true when the binding is constructed by WarmUp.
+	Eager bool `json:"eager,omitempty"`
+	// Failing is true when the binding remembers a failed resolution, failing without calling the resolver.
+	Failing bool `json:"failing,omitempty"`
+}
+
+// Registrations returns the bindings visible from the container sorted by type and name.
+// Bindings registered in a scope shadow bindings with the same type and name in its parents.
+func (c *Container) Registrations() []Registration {
+	visible := c.visibleBindings()
+	registrations := make([]Registration, 0, len(visible))
+
+	for _, v := range visible {
+		v.binding.mu.Lock()
+		registrations = append(registrations, Registration{
+			Type:             v.t.String(),
+			Name:             v.name,
+			Lifetime:         v.binding.lifetime,
+			Scope:            v.depth,
+			Instance:         v.binding.resolver == nil,
+			Instantiated:     v.binding.constructed,
+			Constructions:    v.binding.constructions,
+			ConstructionTime: v.binding.constructionTime,
+			FailurePolicy:    v.binding.failurePolicy,
+			ScopeKind:        v.binding.scopeKind,
+			Eager:            v.binding.eager,
+			Failing:          v.binding.failure.active(v.binding.failurePolicy.now()),
+		})
+		v.binding.mu.Unlock()
+	}
+
+	return registrations
+}
+
+// ActiveScopes returns the number of scopes created from the container, directly or through nested scopes, that are not closed yet.
+func (c *Container) ActiveScopes() int {
+	return int(c.activeScopes.Load())
+}
+
+// visibleBinding is a binding along with its key and the container that owns it.
+type visibleBinding struct {
+	t       reflect.Type
+	name    string
+	binding *binding
+	scope   *Container
+	depth   int
+}
+
+// visibleBindings returns the bindings that can be resolved from the container sorted by type and name.
+// A binding registered in a scope shadows the binding registered with the same type and name in its parents.
+func (c *Container) visibleBindings() []visibleBinding {
+	depth := 0
+	for current := c.parent; current != nil; current = current.parent {
+		depth++
+	}
+	scopeDepth := depth
+
+	type key struct {
+		t    reflect.Type
+		name string
+	}
+
+	seen := map[key]bool{}
+	visible := []visibleBinding{}
+
+	for current := c; current != nil; current, depth = current.parent, depth-1 {
+		current.mu.RLock()
+		for t, named := range current.bindings {
+			for name, binding := range named {
+				if seen[key{t, name}] {
+					continue
+				}
+
+				seen[key{t, name}] = true
+				visible = append(visible, visibleBinding{t: t, name: name, binding: binding, scope: current, depth: depth})
+			}
+		}
+		current.mu.RUnlock()
+	}
+
+	// The scoped bindings registered in the parents are owned by the scope resolving them.
+	for i, v := range visible {
+		if v.scope != c && v.binding.lifetime == Scoped && v.binding.resolver != nil {
+			visible[i].binding, visible[i].scope, visible[i].depth = c.scopedBinding(v.binding), c, scopeDepth
+		}
+	}
+
+	sort.Slice(visible, func(i, j int) bool {
+		if visible[i].t.String() != visible[j].t.String() {
+			return visible[i].t.String() < visible[j].t.String()
+		}
+		return visible[i].name < visible[j].name
+	})
+
+	return visible
+}
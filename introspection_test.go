@@ -0,0 +1,95 @@
+package container_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+func TestContainer_Registrations(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterSingleton(func() Shape {
+		return &Circle{a: 5}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterNamedTransient("mysql", func() Database {
+		return &MySQL{}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterInstance(&DatabaseOptions{})
+	assert.NoError(t, err)
+
+	var s Shape
+	err = c.Resolve(context.Background(), &s)
+	assert.NoError(t, err)
+	err = c.Resolve(context.Background(), &s)
+	assert.NoError(t, err)
+
+	registrations := c.Registrations()
+	assert.Len(t, registrations, 3)
+
+	options := registrations[0]
+	assert.Equal(t, "*container_test.DatabaseOptions", options.Type)
+	assert.True(t, options.Instance)
+	assert.True(t, options.Instantiated)
+	assert.Equal(t, 0, options.Constructions)
+
+	database := registrations[1]
+	assert.Equal(t, "container_test.Database", database.Type)
+	assert.Equal(t, "mysql", database.Name)
+	assert.Equal(t, container.Transient, database.Lifetime)
+	assert.False(t, database.Instantiated)
+
+	shape := registrations[2]
+	assert.Equal(t, "container_test.Shape", shape.Type)
+	assert.True(t, shape.Instantiated)
+	assert.Equal(t, 1, shape.Constructions)
+}
+
+func TestContainer_Registrations_In_Scope(t *testing.T) {
+	root := container.New()
+
+	err := root.RegisterSingleton(func() Shape {
+		return &Circle{a: 5}
+	})
+	assert.NoError(t, err)
+
+	scope, err := root.NewScope()
+	assert.NoError(t, err)
+
+	err = scope.RegisterSingleton(func() Shape {
+		return &Square{a: 5}
+	})
+	assert.NoError(t, err)
+
+	registrations := scope.Registrations()
+	assert.Len(t, registrations, 1)
+	assert.Equal(t, 1, registrations[0].Scope)
+}
+
+func TestContainer_ActiveScopes(t *testing.T) {
+	root := container.New()
+	assert.Equal(t, 0, root.ActiveScopes())
+
+	scope1, err := root.NewScope()
+	assert.NoError(t, err)
+
+	scope2, err := scope1.NewScope()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, root.ActiveScopes())
+	assert.Equal(t, 1, scope1.ActiveScopes())
+
+	assert.NoError(t, scope2.Close())
+	assert.NoError(t, scope2.Close())
+	assert.Equal(t, 1, root.ActiveScopes())
+	assert.Equal(t, 0, scope1.ActiveScopes())
+
+	assert.NoError(t, scope1.Close())
+	assert.Equal(t, 0, root.ActiveScopes())
+}
@@ -0,0 +1,161 @@
+package container
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OpenScope describes a scope that is not closed yet, tracked with WithLeakDetection.
+type OpenScope struct {
+	Kind  string
+	Depth int // Depth is the depth of the scope, 1 for the scopes of the root container.
+	// Created is the time the scope was created at.
+	Created time.Time
+	// Stack is the stack trace of the call to NewScope that created the scope.
+	Stack string
+}
+
+// WithLeakDetection tracks the scopes created from the container and its scopes until they are closed, see
+// OpenScopes. The scopes garbage collected without being closed are reported to onLeak, which is called from the
+// goroutine running the finalizers and may be nil. Tracking captures a stack trace for every scope, so it is meant for
+// debugging and tests.
+func WithLeakDetection(onLeak func(leak OpenScope)) Option {
+	return func(c *Container) {
+		c.leaks = &leakTracker{onLeak: onLeak, open: map[uint64]*trackedScope{}}
+		c.leakID = c.leaks.next()
+	}
+}
+
+// OpenScopes returns the scopes created from the container, directly or through nested scopes, that are not closed
+// yet, in order of creation. It returns nil unless the container was created with WithLeakDetection.
+func (c *Container) OpenScopes() []OpenScope {
+	if c.leaks == nil {
+		return nil
+	}
+
+	return c.leaks.descendants(c.leakID)
+}
+
+// leakTracker holds the open scopes of a container tree. It only holds their descriptions so the scopes can be
+// garbage collected.
+type leakTracker struct {
+	onLeak func(leak OpenScope)
+
+	mu     sync.Mutex
+	lastID uint64
+	open   map[uint64]*trackedScope
+}
+
+// leakToken is owned by a tracked scope and finalized along with it. It does not point back to the scope, so the
+// scope is collected and reported even when it references itself, through the request held by a resolver for example.
+type leakToken struct {
+	tracker *leakTracker
+	id      uint64
+}
+
+type trackedScope struct {
+	id        uint64
+	ancestors []uint64 // ancestors are the ids of the containers the scope descends from.
+	scope     OpenScope
+}
+
+func (t *leakTracker) next() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastID++
+	return t.lastID
+}
+
+// trackScope records the scope as open until it is closed, reporting it if it is garbage collected before.
+// It must be called by NewScope for the stack trace to start at its caller.
+func (c *Container) trackScope(scope *Container) {
+	if c.leaks == nil {
+		return
+	}
+
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+
+	var stack strings.Builder
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&stack, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+
+	tracked := &trackedScope{scope: OpenScope{Kind: scope.kind, Created: time.Now(), Stack: stack.String()}}
+	for current := c; current != nil; current = current.parent {
+		tracked.ancestors = append(tracked.ancestors, current.leakID)
+		tracked.scope.Depth++
+	}
+
+	scope.leaks = c.leaks
+	scope.leakID = c.leaks.next()
+	tracked.id = scope.leakID
+
+	c.leaks.mu.Lock()
+	c.leaks.open[scope.leakID] = tracked
+	c.leaks.mu.Unlock()
+
+	scope.leakToken = &leakToken{tracker: c.leaks, id: scope.leakID}
+	runtime.SetFinalizer(scope.leakToken, func(token *leakToken) {
+		token.tracker.leaked(token.id)
+	})
+}
+
+// untrackScope forgets the scope once it is closed.
+func (c *Container) untrackScope() {
+	if c.leakToken == nil {
+		return
+	}
+
+	runtime.SetFinalizer(c.leakToken, nil)
+
+	c.leaks.mu.Lock()
+	delete(c.leaks.open, c.leakID)
+	c.leaks.mu.Unlock()
+}
+
+// leaked reports the scope garbage collected without being closed.
+func (t *leakTracker) leaked(id uint64) {
+	t.mu.Lock()
+	tracked, exist := t.open[id]
+	delete(t.open, id)
+	t.mu.Unlock()
+
+	if exist && t.onLeak != nil {
+		t.onLeak(tracked.scope)
+	}
+}
+
+func (t *leakTracker) descendants(id uint64) []OpenScope {
+	t.mu.Lock()
+	tracked := []*trackedScope{}
+	for _, scope := range t.open {
+		for _, ancestor := range scope.ancestors {
+			if ancestor == id {
+				tracked = append(tracked, scope)
+				break
+			}
+		}
+	}
+	t.mu.Unlock()
+
+	sort.Slice(tracked, func(i, j int) bool {
+		return tracked[i].id < tracked[j].id
+	})
+
+	open := make([]OpenScope, 0, len(tracked))
+	for _, scope := range tracked {
+		open = append(open, scope.scope)
+	}
+
+	return open
+}
@@ -0,0 +1,134 @@
+package container_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+func TestContainer_OpenScopes(t *testing.T) {
+	c := container.New(container.WithLeakDetection(nil))
+
+	request, err := c.NewScope(container.WithKind("request"))
+	assert.NoError(t, err)
+	nested, err := request.NewScope()
+	assert.NoError(t, err)
+
+	open := c.OpenScopes()
+	assert.Len(t, open, 2)
+	assert.Equal(t, "request", open[0].Kind)
+	assert.Equal(t, 1, open[0].Depth)
+	assert.Equal(t, 2, open[1].Depth)
+	assert.Contains(t, open[0].Stack, "TestContainer_OpenScopes")
+	assert.NotContains(t, open[0].Stack, "NewScope")
+	assert.Len(t, request.OpenScopes(), 1)
+
+	assert.NoError(t, nested.Close())
+	assert.Len(t, c.OpenScopes(), 1)
+	assert.Empty(t, request.OpenScopes())
+
+	assert.NoError(t, request.Close())
+	assert.Empty(t, c.OpenScopes())
+}
+
+func TestContainer_OpenScopes_Without_Leak_Detection(t *testing.T) {
+	c := container.New()
+
+	_, err := c.NewScope()
+	assert.NoError(t, err)
+	assert.Nil(t, c.OpenScopes())
+}
+
+func leakScope(c *container.Container) {
+	_, _ = c.NewScope(container.WithKind("job"))
+}
+
+func TestContainer_Leak_Detection_Reports_Unclosed_Scope(t *testing.T) {
+	leaks := make(chan container.OpenScope, 1)
+	c := container.New(container.WithLeakDetection(func(leak container.OpenScope) {
+		leaks <- leak
+	}))
+
+	leakScope(c)
+
+	deadline := time.After(5 * time.Second)
+	for {
+		runtime.GC()
+
+		select {
+		case leak := <-leaks:
+			assert.Equal(t, "job", leak.Kind)
+			assert.Contains(t, leak.Stack, "leakScope")
+			assert.Empty(t, c.OpenScopes())
+			return
+		case <-deadline:
+			t.Fatal("the unclosed scope was not reported")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+type RequestInfo struct {
+	ctx context.Context
+}
+
+// leakSelfReferencingScope leaks a scope holding a resolver that captures a context carrying the scope.
+func leakSelfReferencingScope(c *container.Container) {
+	var ctx context.Context
+	scope, _ := c.NewScope(container.WithRegistration(container.RegisterOptions{
+		Resolver: func() *RequestInfo {
+			return &RequestInfo{ctx: ctx}
+		},
+		Lifetime: container.Scoped,
+	}))
+	ctx = container.WithContainer(context.Background(), scope)
+}
+
+func TestContainer_Leak_Detection_Reports_Self_Referencing_Scope(t *testing.T) {
+	leaks := make(chan container.OpenScope, 1)
+	c := container.New(container.WithLeakDetection(func(leak container.OpenScope) {
+		leaks <- leak
+	}))
+
+	leakSelfReferencingScope(c)
+
+	deadline := time.After(5 * time.Second)
+	for {
+		runtime.GC()
+
+		select {
+		case leak := <-leaks:
+			assert.Contains(t, leak.Stack, "leakSelfReferencingScope")
+			assert.Empty(t, c.OpenScopes())
+			return
+		case <-deadline:
+			t.Fatal("the unclosed scope was not reported")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestContainer_Leak_Detection_Ignores_Closed_Scope(t *testing.T) {
+	leaks := make(chan container.OpenScope, 1)
+	c := container.New(container.WithLeakDetection(func(leak container.OpenScope) {
+		leaks <- leak
+	}))
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+	assert.NoError(t, scope.Close())
+	scope = nil
+
+	runtime.GC()
+	runtime.GC()
+
+	select {
+	case <-leaks:
+		t.Fatal("a closed scope was reported")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
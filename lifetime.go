@@ -0,0 +1,219 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// LifetimeStrategy decides whether resolving a binding constructs a new concrete or reuses one.
+// Strategies are registered for a Lifetime with RegisterLifetime; Singleton, Transient, Scoped and Pooled are built in.
+// Implementations must be safe to use from multiple goroutines.
+type LifetimeStrategy interface {
+	// Resolve returns the concrete for the request, and whether it was reused instead of being constructed.
+	Resolve(ctx context.Context, request LifetimeRequest) (interface{}, bool, error)
+}
+
+// LifetimeStrategyFunc adapts a function to the LifetimeStrategy interface.
+type LifetimeStrategyFunc func(ctx context.Context, request LifetimeRequest) (interface{}, bool, error)
+
+func (f LifetimeStrategyFunc) Resolve(ctx context.Context, request LifetimeRequest) (interface{}, bool, error) {
+	return f(ctx, request)
+}
+
+// LifetimeRequest is the resolution of a binding handed to the strategy of its lifetime.
+type LifetimeRequest struct {
+	Type     reflect.Type
+	Name     string
+	Lifetime Lifetime
+	// Scope is the container performing the resolution, which may be a scope of the container the binding is
+	// registered in.
+	Scope *Container
+
+	binding *binding
+}
+
+// Key returns a comparable value identifying the binding, for strategies keeping concretes by binding.
+// Each registration has its own key, and so do the copies of scoped bindings made for every scope.
+func (r LifetimeRequest) Key() interface{} {
+	return r.binding
+}
+
+// Construct calls the resolver of the binding, then the injection methods and Init on the concrete.
+// Every call constructs a new concrete.
+func (r LifetimeRequest) Construct(ctx context.Context) (interface{}, error) {
+	return r.binding.construct(ctx, r.Scope)
+}
+
+// Cached returns the concrete stored in the binding with Cache and whether there is one, the concrete may be nil.
+func (r LifetimeRequest) Cached() (interface{}, bool) {
+	return r.binding.cached()
+}
+
+// Cache stores the concrete in the binding.
+func (r LifetimeRequest) Cache(concrete interface{}) {
+	r.binding.mu.Lock()
+	defer r.binding.mu.Unlock()
+
+	r.binding.concrete = concrete
+	r.binding.constructed = true
+}
+
+// cachingLifetime constructs a concrete once and reuses it for every resolution of the binding.
+// It implements Singleton and, since every scope holds its own copy of the scoped bindings, Scoped.
+type cachingLifetime struct{}
+
+func (cachingLifetime) Resolve(ctx context.Context, request LifetimeRequest) (interface{}, bool, error) {
+	if concrete, ok := request.Cached(); ok {
+		return concrete, true, nil
+	}
+
+	// Concurrent resolutions wait for the one constructing the concrete. A resolution from the goroutine constructing
+	// it comes from its own resolver through the container, which would wait forever.
+	goroutine := goroutineID()
+	if goroutine != 0 && request.binding.builder.Load() == goroutine {
+		return nil, false, fmt.Errorf("%w, '%s' depends on itself", ErrCircularDependency, request.Type.String())
+	}
+
+	request.binding.build.Lock()
+	defer request.binding.build.Unlock()
+
+	request.binding.builder.Store(goroutine)
+	defer request.binding.builder.Store(0)
+
+	if concrete, ok := request.Cached(); ok {
+		return concrete, true, nil
+	}
+
+	concrete, err := request.Construct(ctx)
+	if err == nil {
+		request.Cache(concrete)
+	}
+
+	return concrete, false, err
+}
+
+// transientLifetime constructs a concrete for every resolution.
+type transientLifetime struct{}
+
+func (transientLifetime) Resolve(ctx context.Context, request LifetimeRequest) (interface{}, bool, error) {
+	concrete, err := request.Construct(ctx)
+	return concrete, false, err
+}
+
+var builtinLifetimes = map[Lifetime]LifetimeStrategy{
+	Singleton: cachingLifetime{},
+	Transient: transientLifetime{},
+	Scoped:    cachingLifetime{},
+	Pooled:    NewPooledLifetime(DefaultPoolSize),
+}
+
+// disposingLifetime is implemented by strategies disposing of the concretes they construct, which are then not
+// tracked by the containers. The container a binding is registered in calls dispose with the binding when it closes.
+type disposingLifetime interface {
+	dispose(b *binding) error
+}
+
+// disposeBindings lets the strategies disposing of their concretes dispose of those of the bindings of the container.
+func (c *Container) disposeBindings() error {
+	c.mu.RLock()
+	bindings := []*binding{}
+	for _, named := range c.bindings {
+		for _, b := range named {
+			bindings = append(bindings, b)
+		}
+	}
+	c.mu.RUnlock()
+
+	var errs []error
+	for _, b := range bindings {
+		if strategy, disposing := c.lifetimeStrategy(b.lifetime).(disposingLifetime); disposing {
+			if err := strategy.dispose(b); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RegisterLifetime registers the strategy of a custom lifetime for the container and the scopes created from it.
+// Bindings can only be registered with built-in lifetimes and lifetimes registered in the container or its parents.
+// Registering a lifetime again replaces its strategy; the built-in lifetimes cannot be replaced.
+func (c *Container) RegisterLifetime(lifetime Lifetime, strategy LifetimeStrategy) error {
+	if lifetime == "" || strategy == nil {
+		return fmt.Errorf("%w, the lifetime and its strategy are required", ErrInvalidLifetime)
+	}
+
+	if _, builtin := builtinLifetimes[lifetime]; builtin {
+		return fmt.Errorf("%w, the built-in lifetime '%s' cannot be replaced", ErrInvalidLifetime, lifetime)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.lifetimes == nil {
+		c.lifetimes = map[Lifetime]LifetimeStrategy{}
+	}
+	c.lifetimes[lifetime] = strategy
+
+	return nil
+}
+
+// lifetimeStrategy returns the strategy of the lifetime, or nil if the lifetime is unknown.
+func (c *Container) lifetimeStrategy(lifetime Lifetime) LifetimeStrategy {
+	if strategy, builtin := builtinLifetimes[lifetime]; builtin {
+		return strategy
+	}
+
+	for current := c; current != nil; current = current.parent {
+		current.mu.RLock()
+		strategy, exist := current.lifetimes[lifetime]
+		current.mu.RUnlock()
+
+		if exist {
+			return strategy
+		}
+	}
+
+	return nil
+}
+
+// construct calls the resolver and prepares the constructed concrete according to the failure policy of the binding.
+func (b *binding) construct(ctx context.Context, c *Container) (interface{}, error) {
+	if err := b.remembered(); err != nil {
+		return nil, err
+	}
+
+	concrete, err := b.attempt(ctx, func() (interface{}, error) {
+		return b.constructOnce(ctx, c)
+	})
+	if err != nil {
+		b.remember(ctx, err)
+	}
+
+	return concrete, err
+}
+
+// constructOnce calls the resolver and prepares the constructed concrete, recording the construction.
+func (b *binding) constructOnce(ctx context.Context, c *Container) (interface{}, error) {
+	start := time.Now()
+	concrete, err := c.invoke(ctx, b.resolver)
+	if err == nil {
+		err = c.injectMethods(ctx, concrete, b.injectMethods)
+	}
+	if err == nil {
+		err = initialize(resolverContext(ctx), concrete)
+	}
+	elapsed := time.Since(start)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.constructions++
+	b.constructionTime += elapsed
+
+	return concrete, err
+}
@@ -0,0 +1,135 @@
+package container_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+type tenantKey struct{}
+
+const PerTenant container.Lifetime = "per-tenant"
+
+// tenantLifetime keeps a concrete per binding and per tenant carried by the context.
+type tenantLifetime struct {
+	mu        sync.Mutex
+	concretes map[[2]interface{}]interface{}
+}
+
+func (l *tenantLifetime) Resolve(ctx context.Context, request container.LifetimeRequest) (interface{}, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := [2]interface{}{request.Key(), ctx.Value(tenantKey{})}
+	if concrete, exist := l.concretes[key]; exist {
+		return concrete, true, nil
+	}
+
+	concrete, err := request.Construct(ctx)
+	if err == nil {
+		l.concretes[key] = concrete
+	}
+
+	return concrete, false, err
+}
+
+func TestContainer_RegisterLifetime(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterLifetime(PerTenant, &tenantLifetime{concretes: map[[2]interface{}]interface{}{}})
+	assert.NoError(t, err)
+
+	err = c.Register(container.RegisterOptions{
+		Resolver: func(ctx context.Context) *DatabaseOptions {
+			return &DatabaseOptions{Host: ctx.Value(tenantKey{}).(string)}
+		},
+		Lifetime: PerTenant,
+	})
+	assert.NoError(t, err)
+
+	contoso := context.WithValue(context.Background(), tenantKey{}, "contoso")
+	fabrikam := context.WithValue(context.Background(), tenantKey{}, "fabrikam")
+
+	var first, second, other *DatabaseOptions
+	assert.NoError(t, c.Resolve(contoso, &first))
+	assert.NoError(t, c.Resolve(contoso, &second))
+	assert.NoError(t, c.Resolve(fabrikam, &other))
+
+	assert.Same(t, first, second)
+	assert.Equal(t, "contoso", first.Host)
+	assert.Equal(t, "fabrikam", other.Host)
+
+	registrations := c.Registrations()
+	assert.Equal(t, PerTenant, registrations[0].Lifetime)
+	assert.Equal(t, 2, registrations[0].Constructions)
+}
+
+func TestContainer_RegisterLifetime_Inherited_By_Scopes(t *testing.T) {
+	c := container.New()
+
+	err := c.RegisterLifetime(PerTenant, &tenantLifetime{concretes: map[[2]interface{}]interface{}{}})
+	assert.NoError(t, err)
+
+	err = c.Register(container.RegisterOptions{
+		Resolver: func(ctx context.Context) *DatabaseOptions {
+			return &DatabaseOptions{Host: ctx.Value(tenantKey{}).(string)}
+		},
+		Lifetime: PerTenant,
+	})
+	assert.NoError(t, err)
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	err = scope.Register(container.RegisterOptions{
+		Resolver: func() Shape { return &Circle{} },
+		Lifetime: PerTenant,
+	})
+	assert.NoError(t, err)
+
+	var options *DatabaseOptions
+	assert.NoError(t, scope.Resolve(context.WithValue(context.Background(), tenantKey{}, "contoso"), &options))
+	assert.Equal(t, "contoso", options.Host)
+}
+
+func TestContainer_Register_With_Unknown_Lifetime_It_Should_Fail(t *testing.T) {
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() Shape { return &Circle{} },
+		Lifetime: "per-job",
+	})
+	assert.ErrorIs(t, err, container.ErrInvalidLifetime)
+
+	err = container.RegisterStruct[*Circle](c, "per-job")
+	assert.ErrorIs(t, err, container.ErrInvalidLifetime)
+
+	// A lifetime registered in a scope is unknown to its parent.
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+	assert.NoError(t, scope.RegisterLifetime("per-job", container.LifetimeStrategyFunc(
+		func(ctx context.Context, request container.LifetimeRequest) (interface{}, bool, error) {
+			concrete, err := request.Construct(ctx)
+			return concrete, false, err
+		})))
+
+	err = scope.Register(container.RegisterOptions{Resolver: func() Shape { return &Circle{} }, Lifetime: "per-job"})
+	assert.NoError(t, err)
+	err = c.Register(container.RegisterOptions{Resolver: func() Shape { return &Circle{} }, Lifetime: "per-job"})
+	assert.ErrorIs(t, err, container.ErrInvalidLifetime)
+}
+
+func TestContainer_RegisterLifetime_With_Invalid_Lifetime_It_Should_Fail(t *testing.T) {
+	c := container.New()
+	strategy := container.LifetimeStrategyFunc(func(ctx context.Context, request container.LifetimeRequest) (interface{}, bool, error) {
+		concrete, err := request.Construct(ctx)
+		return concrete, false, err
+	})
+
+	assert.ErrorIs(t, c.RegisterLifetime(container.Singleton, strategy), container.ErrInvalidLifetime)
+	assert.ErrorIs(t, c.RegisterLifetime("", strategy), container.ErrInvalidLifetime)
+	assert.ErrorIs(t, c.RegisterLifetime("per-job", nil), container.ErrInvalidLifetime)
+}
@@ -0,0 +1,148 @@
+package container
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"time"
+)
+
+// LogEventKind identifies the activity reported by a LogEvent.
+type LogEventKind string
+
+const (
+	// LogRegistration is reported when a binding is registered.
+	LogRegistration LogEventKind = "registration"
+	// LogOverwrite is reported when a binding replaces a binding registered with the same type and name.
+	LogOverwrite LogEventKind = "overwrite"
+	// LogResolution is reported when a binding is resolved by constructing a concrete, or fails to resolve.
+	LogResolution LogEventKind = "resolution"
+	// LogCacheHit is reported when a binding is resolved with a cached concrete.
+	LogCacheHit LogEventKind = "cache_hit"
+	// LogScope is reported when a scope is created.
+	LogScope LogEventKind = "scope"
+	// LogDisposal is reported when a constructed concrete is closed along with its container, see WithDisposal.
+	LogDisposal LogEventKind = "disposal"
+)
+
+// LogEvent is a structured record of the container activity.
+type LogEvent struct {
+	Kind  LogEventKind
+	Level slog.Level
+	// Type is the abstraction of the binding, or the type of the closed concrete for LogDisposal events.
+	Type     reflect.Type
+	Name     string
+	Lifetime Lifetime
+	Duration time.Duration
+	Err      error
+}
+
+// Logger receives the events of the containers it is attached to with WithLogger.
+// Implementations must be safe to use from multiple goroutines.
+type Logger interface {
+	Log(ctx context.Context, event LogEvent)
+}
+
+// LogLevels are the levels events are reported at, by kind.
+// Events carrying an error are reported at the Error level whatever their kind.
+type LogLevels struct {
+	Registration slog.Level
+	Overwrite    slog.Level
+	Resolution   slog.Level
+	CacheHit     slog.Level
+	Scope        slog.Level
+	Disposal     slog.Level
+	Error        slog.Level
+}
+
+// DefaultLogLevels reports overwrites as warnings, failures as errors and everything else at the debug level.
+var DefaultLogLevels = LogLevels{
+	Registration: slog.LevelDebug,
+	Overwrite:    slog.LevelWarn,
+	Resolution:   slog.LevelDebug,
+	CacheHit:     slog.LevelDebug,
+	Scope:        slog.LevelDebug,
+	Disposal:     slog.LevelDebug,
+	Error:        slog.LevelError,
+}
+
+// WithLogger attaches the logger to the container and the scopes created from it.
+func WithLogger(logger Logger) Option {
+	return func(c *Container) {
+		c.logger = logger
+	}
+}
+
+// WithLogLevels overrides DefaultLogLevels for the container and the scopes created from it.
+func WithLogLevels(levels LogLevels) Option {
+	return func(c *Container) {
+		c.logLevels = levels
+	}
+}
+
+// level returns the level the event is reported at.
+func (l LogLevels) level(event LogEvent) slog.Level {
+	if event.Err != nil {
+		return l.Error
+	}
+
+	switch event.Kind {
+	case LogRegistration:
+		return l.Registration
+	case LogOverwrite:
+		return l.Overwrite
+	case LogCacheHit:
+		return l.CacheHit
+	case LogScope:
+		return l.Scope
+	case LogDisposal:
+		return l.Disposal
+	default:
+		return l.Resolution
+	}
+}
+
+// log reports the event to the logger of the container, if any.
+func (c *Container) log(ctx context.Context, event LogEvent) {
+	if c.logger == nil {
+		return
+	}
+
+	event.Level = c.logLevels.level(event)
+	c.logger.Log(ctx, event)
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger returns a Logger writing the events to the slog logger.
+// The kind, type, name, lifetime, duration and error of the events are reported as attributes when set.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return &slogLogger{logger: logger}
+}
+
+func (l *slogLogger) Log(ctx context.Context, event LogEvent) {
+	if !l.logger.Enabled(ctx, event.Level) {
+		return
+	}
+
+	attributes := []slog.Attr{slog.String("kind", string(event.Kind))}
+	if event.Type != nil {
+		attributes = append(attributes, slog.String("type", event.Type.String()))
+	}
+	if event.Name != "" {
+		attributes = append(attributes, slog.String("name", event.Name))
+	}
+	if event.Lifetime != "" {
+		attributes = append(attributes, slog.String("lifetime", string(event.Lifetime)))
+	}
+	if event.Duration != 0 {
+		attributes = append(attributes, slog.Duration("duration", event.Duration))
+	}
+	if event.Err != nil {
+		attributes = append(attributes, slog.Any("error", event.Err))
+	}
+
+	l.logger.LogAttrs(ctx, event.Level, "container "+string(event.Kind), attributes...)
+}
@@ -0,0 +1,163 @@
+package container_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+type recordingLogger struct {
+	mu     sync.Mutex
+	events []container.LogEvent
+}
+
+func (l *recordingLogger) Log(ctx context.Context, event container.LogEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.events = append(l.events, event)
+}
+
+func (l *recordingLogger) kinds() []container.LogEventKind {
+	kinds := []container.LogEventKind{}
+	for _, event := range l.events {
+		kinds = append(kinds, event.Kind)
+	}
+
+	return kinds
+}
+
+func TestLogger_Registration_And_Resolution_Events(t *testing.T) {
+	logger := &recordingLogger{}
+	c := container.New(container.WithLogger(logger))
+
+	container.MustRegisterSingleton(c, func() Shape {
+		return &Circle{a: 5}
+	})
+	container.MustRegisterSingleton(c, func() Shape {
+		return &Square{a: 5}
+	})
+
+	var s Shape
+	container.MustResolve(context.Background(), c, &s)
+	container.MustResolve(context.Background(), c, &s)
+
+	assert.Equal(t, []container.LogEventKind{
+		container.LogRegistration,
+		container.LogOverwrite,
+		container.LogResolution,
+		container.LogCacheHit,
+	}, logger.kinds())
+
+	assert.Equal(t, slog.LevelDebug, logger.events[0].Level)
+	assert.Equal(t, slog.LevelWarn, logger.events[1].Level)
+	assert.Equal(t, "container_test.Shape", logger.events[2].Type.String())
+	assert.Equal(t, container.Singleton, logger.events[2].Lifetime)
+}
+
+func TestLogger_Failed_Resolution_Events(t *testing.T) {
+	logger := &recordingLogger{}
+	c := container.New(container.WithLogger(logger))
+
+	var db Database
+	err := c.Resolve(context.Background(), &db)
+	assert.Error(t, err)
+
+	resolveErr := errors.New("app: cannot connect")
+	container.MustRegisterNamedSingleton(c, "primary", func() (Database, error) {
+		return nil, resolveErr
+	})
+
+	err = c.ResolveNamed(context.Background(), "primary", &db)
+	assert.Error(t, err)
+
+	assert.Len(t, logger.events, 3)
+	assert.ErrorIs(t, logger.events[0].Err, container.ErrBindingNotFound)
+	assert.Equal(t, slog.LevelError, logger.events[0].Level)
+	assert.ErrorIs(t, logger.events[2].Err, resolveErr)
+	assert.Equal(t, "primary", logger.events[2].Name)
+	assert.Equal(t, slog.LevelError, logger.events[2].Level)
+}
+
+func TestLogger_Is_Inherited_By_Scopes(t *testing.T) {
+	logger := &recordingLogger{}
+	c := container.New(container.WithLogger(logger), container.WithDisposal())
+
+	container.MustRegisterScoped(c, func() *Connection {
+		return &Connection{}
+	})
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	var connection *Connection
+	container.MustResolve(context.Background(), scope, &connection)
+
+	err = scope.Close()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []container.LogEventKind{
+		container.LogRegistration,
+		container.LogScope,
+		container.LogResolution,
+		container.LogDisposal,
+	}, logger.kinds())
+	assert.Equal(t, "*container_test.Connection", logger.events[3].Type.String())
+}
+
+func TestLogger_With_Custom_Levels(t *testing.T) {
+	logger := &recordingLogger{}
+	levels := container.DefaultLogLevels
+	levels.Registration = slog.LevelInfo
+
+	c := container.New(container.WithLogger(logger), container.WithLogLevels(levels))
+
+	container.MustRegisterInstance(c, &Circle{})
+
+	assert.Len(t, logger.events, 1)
+	assert.Equal(t, slog.LevelInfo, logger.events[0].Level)
+}
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	c := container.New(container.WithLogger(container.NewSlogLogger(slog.New(handler))))
+
+	container.MustRegisterNamedSingleton(c, "round", func() Shape {
+		return &Circle{a: 5}
+	})
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 1)
+
+	var record map[string]interface{}
+	err := json.Unmarshal([]byte(lines[0]), &record)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "DEBUG", record["level"])
+	assert.Equal(t, "container registration", record["msg"])
+	assert.Equal(t, "registration", record["kind"])
+	assert.Equal(t, "container_test.Shape", record["type"])
+	assert.Equal(t, "round", record["name"])
+	assert.Equal(t, "singleton", record["lifetime"])
+}
+
+func TestSlogLogger_Respects_Handler_Level(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})
+	c := container.New(container.WithLogger(container.NewSlogLogger(slog.New(handler))))
+
+	container.MustRegisterInstance(c, &Circle{})
+	assert.Empty(t, buf.String())
+
+	container.MustRegisterInstance(c, &Circle{})
+	assert.Contains(t, buf.String(), `"kind":"overwrite"`)
+}
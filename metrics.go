@@ -0,0 +1,281 @@
+package container
+
+import (
+	"expvar"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Resolution describes the outcome of resolving a binding.
+type Resolution struct {
+	Type     reflect.Type
+	Name     string
+	Lifetime Lifetime
+	// Cached is true when the concrete was returned from the cache instead of being constructed.
+	Cached   bool
+	Duration time.Duration
+	Err      error
+}
+
+// MetricsCollector receives measurements from the containers it is attached to with WithMetrics.
+// Implementations must be safe to use from multiple goroutines.
+type MetricsCollector interface {
+	// ResolutionCompleted is called after every resolution of a binding, successful or not.
+	ResolutionCompleted(resolution Resolution)
+	// ScopeOpened is called when a scope is created with NewScope.
+	ScopeOpened()
+	// ScopeClosed is called when a scope is closed.
+	ScopeClosed()
+	// DisposableTracked is called when a constructed concrete implementing io.Closer is tracked by a container created
+	// with WithDisposal.
+	DisposableTracked()
+	// DisposableClosed is called when a tracked concrete is closed along with its container.
+	DisposableClosed()
+}
+
+// WithMetrics attaches the metrics collector to the container and the scopes created from it.
+func WithMetrics(metrics MetricsCollector) Option {
+	return func(c *Container) {
+		c.metrics = metrics
+	}
+}
+
+// DefaultLatencyBuckets are the upper bounds of the construction latency histograms.
+var DefaultLatencyBuckets = []time.Duration{
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+}
+
+// Histogram counts observed durations in buckets.
+// Counts[i] is the number of durations less than or equal to Buckets[i] and greater than the previous bucket,
+// the last count holding the durations greater than every bucket.
+type Histogram struct {
+	Buckets []time.Duration `json:"buckets"`
+	Counts  []int           `json:"counts"`
+	Count   int             `json:"count"`
+	Sum     time.Duration   `json:"sum"`
+}
+
+func newHistogram(buckets []time.Duration) Histogram {
+	return Histogram{
+		Buckets: buckets,
+		Counts:  make([]int, len(buckets)+1),
+	}
+}
+
+func (h *Histogram) observe(duration time.Duration) {
+	h.Count++
+	h.Sum += duration
+
+	for i, bucket := range h.Buckets {
+		if duration <= bucket {
+			h.Counts[i]++
+			return
+		}
+	}
+
+	h.Counts[len(h.Buckets)]++
+}
+
+// TypeMetrics holds the measurements for a binding.
+type TypeMetrics struct {
+	Resolutions int `json:"resolutions"`
+	CacheHits   int `json:"cacheHits"`
+	Failures    int `json:"failures"`
+	// ConstructionLatency holds the durations of the resolutions that constructed a concrete.
+	ConstructionLatency Histogram `json:"constructionLatency"`
+}
+
+// CacheHitRatio returns the ratio of resolutions returned from the cache, or 0 when there was no resolution.
+func (m TypeMetrics) CacheHitRatio() float64 {
+	if m.Resolutions == 0 {
+		return 0
+	}
+
+	return float64(m.CacheHits) / float64(m.Resolutions)
+}
+
+// MetricsSnapshot is a copy of the measurements held by a MemoryMetrics.
+type MetricsSnapshot struct {
+	// Types holds the measurements by binding, keyed by the id of the binding in the Graph: its type with the import
+	// paths of the packages, followed by `#name` for named bindings.
+	Types           map[string]TypeMetrics `json:"types"`
+	OpenScopes      int                    `json:"openScopes"`
+	OpenDisposables int                    `json:"openDisposables"`
+}
+
+// MemoryMetrics is a MetricsCollector keeping the measurements in memory.
+type MemoryMetrics struct {
+	mu       sync.Mutex
+	buckets  []time.Duration
+	snapshot MetricsSnapshot
+}
+
+// NewMemoryMetrics creates a MemoryMetrics using DefaultLatencyBuckets.
+func NewMemoryMetrics() *MemoryMetrics {
+	return &MemoryMetrics{
+		buckets:  DefaultLatencyBuckets,
+		snapshot: MetricsSnapshot{Types: map[string]TypeMetrics{}},
+	}
+}
+
+// Snapshot returns a copy of the current measurements.
+func (m *MemoryMetrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := m.snapshot
+	snapshot.Types = make(map[string]TypeMetrics, len(m.snapshot.Types))
+	for key, metrics := range m.snapshot.Types {
+		metrics.ConstructionLatency.Counts = append([]int{}, metrics.ConstructionLatency.Counts...)
+		snapshot.Types[key] = metrics
+	}
+
+	return snapshot
+}
+
+func (m *MemoryMetrics) ResolutionCompleted(resolution Resolution) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := graphNodeID(resolution.Type, resolution.Name)
+	metrics, exist := m.snapshot.Types[key]
+	if !exist {
+		metrics.ConstructionLatency = newHistogram(m.buckets)
+	}
+
+	metrics.Resolutions++
+	if resolution.Err != nil {
+		metrics.Failures++
+	} else if resolution.Cached {
+		metrics.CacheHits++
+	} else {
+		metrics.ConstructionLatency.observe(resolution.Duration)
+	}
+
+	m.snapshot.Types[key] = metrics
+}
+
+func (m *MemoryMetrics) ScopeOpened() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.snapshot.OpenScopes++
+}
+
+func (m *MemoryMetrics) ScopeClosed() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.snapshot.OpenScopes--
+}
+
+func (m *MemoryMetrics) DisposableTracked() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.snapshot.OpenDisposables++
+}
+
+func (m *MemoryMetrics) DisposableClosed() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.snapshot.OpenDisposables--
+}
+
+// ExpvarMetrics is a MetricsCollector publishing the measurements with the expvar package.
+// The published map holds the `resolutions`, `cacheHits` and `failures` maps keyed by binding,
+// the `constructionLatency` map holding a histogram by binding, and the `openScopes` and `openDisposables` counters.
+type ExpvarMetrics struct {
+	mu                  sync.Mutex
+	buckets             []time.Duration
+	resolutions         *expvar.Map
+	cacheHits           *expvar.Map
+	failures            *expvar.Map
+	constructionLatency *expvar.Map
+	openScopes          *expvar.Int
+	openDisposables     *expvar.Int
+}
+
+// NewExpvarMetrics creates an ExpvarMetrics and publishes its measurements under the name.
+// Like expvar.Publish, it panics if the name is already in use.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := &ExpvarMetrics{
+		buckets:             DefaultLatencyBuckets,
+		resolutions:         new(expvar.Map).Init(),
+		cacheHits:           new(expvar.Map).Init(),
+		failures:            new(expvar.Map).Init(),
+		constructionLatency: new(expvar.Map).Init(),
+		openScopes:          new(expvar.Int),
+		openDisposables:     new(expvar.Int),
+	}
+
+	published := expvar.NewMap(name)
+	published.Set("resolutions", m.resolutions)
+	published.Set("cacheHits", m.cacheHits)
+	published.Set("failures", m.failures)
+	published.Set("constructionLatency", m.constructionLatency)
+	published.Set("openScopes", m.openScopes)
+	published.Set("openDisposables", m.openDisposables)
+
+	return m
+}
+
+func (m *ExpvarMetrics) ResolutionCompleted(resolution Resolution) {
+	key := graphNodeID(resolution.Type, resolution.Name)
+
+	m.resolutions.Add(key, 1)
+	if resolution.Err != nil {
+		m.failures.Add(key, 1)
+		return
+	}
+
+	if resolution.Cached {
+		m.cacheHits.Add(key, 1)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	histogram, _ := m.constructionLatency.Get(key).(*expvar.Map)
+	if histogram == nil {
+		histogram = new(expvar.Map).Init()
+		m.constructionLatency.Set(key, histogram)
+	}
+
+	bucket := "+Inf"
+	for _, upper := range m.buckets {
+		if resolution.Duration <= upper {
+			bucket = upper.String()
+			break
+		}
+	}
+
+	histogram.Add(bucket, 1)
+	histogram.Add("count", 1)
+	histogram.Add("sumNanoseconds", int64(resolution.Duration))
+}
+
+func (m *ExpvarMetrics) ScopeOpened() {
+	m.openScopes.Add(1)
+}
+
+func (m *ExpvarMetrics) ScopeClosed() {
+	m.openScopes.Add(-1)
+}
+
+func (m *ExpvarMetrics) DisposableTracked() {
+	m.openDisposables.Add(1)
+}
+
+func (m *ExpvarMetrics) DisposableClosed() {
+	m.openDisposables.Add(-1)
+}
@@ -0,0 +1,165 @@
+package container_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	htmltemplate "html/template"
+	"reflect"
+	"testing"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+type Connection struct {
+	closed bool
+}
+
+func (c *Connection) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestMemoryMetrics_Resolutions(t *testing.T) {
+	metrics := container.NewMemoryMetrics()
+	c := container.New(container.WithMetrics(metrics))
+
+	container.MustRegisterSingleton(c, func() Shape {
+		return &Circle{a: 5}
+	})
+	container.MustRegisterNamedTransient(c, "broken", func() (Database, error) {
+		return nil, errors.New("app: broken")
+	})
+
+	var s Shape
+	for i := 0; i < 4; i++ {
+		err := c.Resolve(context.Background(), &s)
+		assert.NoError(t, err)
+	}
+
+	var db Database
+	err := c.ResolveNamed(context.Background(), "broken", &db)
+	assert.Error(t, err)
+
+	snapshot := metrics.Snapshot()
+
+	shape := snapshot.Types[testPackage+".Shape"]
+	assert.Equal(t, 4, shape.Resolutions)
+	assert.Equal(t, 3, shape.CacheHits)
+	assert.Equal(t, 0.75, shape.CacheHitRatio())
+	assert.Equal(t, 1, shape.ConstructionLatency.Count)
+	assert.Len(t, shape.ConstructionLatency.Counts, len(container.DefaultLatencyBuckets)+1)
+
+	broken := snapshot.Types[testPackage+".Database#broken"]
+	assert.Equal(t, 1, broken.Resolutions)
+	assert.Equal(t, 1, broken.Failures)
+	assert.Equal(t, 0.0, broken.CacheHitRatio())
+}
+
+func TestMemoryMetrics_Scopes_And_Disposables(t *testing.T) {
+	metrics := container.NewMemoryMetrics()
+	c := container.New(container.WithMetrics(metrics), container.WithDisposal())
+
+	container.MustRegisterScoped(c, func() *Connection {
+		return &Connection{}
+	})
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	var connection *Connection
+	err = scope.Resolve(context.Background(), &connection)
+	assert.NoError(t, err)
+	err = scope.Resolve(context.Background(), &connection)
+	assert.NoError(t, err)
+
+	snapshot := metrics.Snapshot()
+	assert.Equal(t, 1, snapshot.OpenScopes)
+	assert.Equal(t, 1, snapshot.OpenDisposables)
+
+	err = scope.Close()
+	assert.NoError(t, err)
+	assert.True(t, connection.closed)
+
+	snapshot = metrics.Snapshot()
+	assert.Equal(t, 0, snapshot.OpenScopes)
+	assert.Equal(t, 0, snapshot.OpenDisposables)
+}
+
+func TestHistogram_Buckets(t *testing.T) {
+	metrics := container.NewMemoryMetrics()
+
+	for _, duration := range []time.Duration{time.Microsecond, 50 * time.Microsecond, time.Minute} {
+		metrics.ResolutionCompleted(container.Resolution{
+			Type:     reflect.TypeOf((*Shape)(nil)).Elem(),
+			Lifetime: container.Transient,
+			Duration: duration,
+		})
+	}
+
+	histogram := metrics.Snapshot().Types[testPackage+".Shape"].ConstructionLatency
+	assert.Equal(t, 3, histogram.Count)
+	assert.Equal(t, time.Minute+51*time.Microsecond, histogram.Sum)
+	assert.Equal(t, []int{1, 1, 0, 0, 0, 0, 0, 1}, histogram.Counts)
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	metrics := container.NewExpvarMetrics("container_test_metrics")
+	c := container.New(container.WithMetrics(metrics), container.WithDisposal())
+
+	container.MustRegisterSingleton(c, func() *Connection {
+		return &Connection{}
+	})
+
+	var connection *Connection
+	err := c.Resolve(context.Background(), &connection)
+	assert.NoError(t, err)
+	err = c.Resolve(context.Background(), &connection)
+	assert.NoError(t, err)
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	var published struct {
+		Resolutions         map[string]int            `json:"resolutions"`
+		CacheHits           map[string]int            `json:"cacheHits"`
+		ConstructionLatency map[string]map[string]int `json:"constructionLatency"`
+		OpenScopes          int                       `json:"openScopes"`
+		OpenDisposables     int                       `json:"openDisposables"`
+	}
+
+	err = json.Unmarshal([]byte(expvar.Get("container_test_metrics").String()), &published)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, published.Resolutions["*"+testPackage+".Connection"])
+	assert.Equal(t, 1, published.CacheHits["*"+testPackage+".Connection"])
+	assert.Equal(t, 1, published.ConstructionLatency["*"+testPackage+".Connection"]["count"])
+	assert.Equal(t, 1, published.OpenScopes)
+	assert.Equal(t, 1, published.OpenDisposables)
+
+	assert.NoError(t, scope.Close())
+	assert.NoError(t, c.Close())
+	assert.True(t, connection.closed)
+	assert.Equal(t, "0", expvar.Get("container_test_metrics").(*expvar.Map).Get("openDisposables").String())
+}
+
+func TestMemoryMetrics_Types_With_The_Same_Name_In_Different_Packages(t *testing.T) {
+	metrics := container.NewMemoryMetrics()
+	c := container.New(container.WithMetrics(metrics))
+
+	assert.NoError(t, container.RegisterInstanceAs(c, texttemplate.New("text")))
+	assert.NoError(t, container.RegisterInstanceAs(c, htmltemplate.New("html")))
+
+	var text *texttemplate.Template
+	var html *htmltemplate.Template
+	assert.NoError(t, c.Resolve(context.Background(), &text))
+	assert.NoError(t, c.Resolve(context.Background(), &html))
+
+	snapshot := metrics.Snapshot()
+	assert.Equal(t, 1, snapshot.Types["*text/template.Template"].Resolutions)
+	assert.Equal(t, 1, snapshot.Types["*html/template.Template"].Resolutions)
+}
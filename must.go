@@ -79,6 +79,26 @@ func MustCall(ctx context.Context, c *Container, receiver interface{}) {
 	}
 }
 
+// MustCallWithResults wraps the `CallWithResults` method and panics on errors instead of returning the errors.
+func MustCallWithResults(ctx context.Context, c *Container, function interface{}, arguments ...Argument) []interface{} {
+	results, err := c.CallWithResults(ctx, function, arguments...)
+	if err != nil {
+		panic(err)
+	}
+
+	return results
+}
+
+// MustInvoke wraps the `Invoke` function and panics on errors instead of returning the errors.
+func MustInvoke[R any](ctx context.Context, c *Container, function interface{}, arguments ...Argument) R {
+	result, err := Invoke[R](ctx, c, function, arguments...)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
 // MustResolve wraps the `Resolve` method and panics on errors instead of returning the errors.
 func MustResolve(ctx context.Context, c *Container, abstraction interface{}) {
 	if err := c.Resolve(ctx, abstraction); err != nil {
@@ -99,3 +119,17 @@ func MustFill(ctx context.Context, c *Container, receiver interface{}) {
 		panic(err)
 	}
 }
+
+// MustRegisterStruct wraps the `RegisterStruct` method and panics on errors instead of returning the errors.
+func MustRegisterStruct[T any](c *Container, lifetime Lifetime) {
+	if err := RegisterStruct[T](c, lifetime); err != nil {
+		panic(err)
+	}
+}
+
+// MustRegisterStructAs wraps the `RegisterStructAs` method and panics on errors instead of returning the errors.
+func MustRegisterStructAs[A any, T any](c *Container, lifetime Lifetime) {
+	if err := RegisterStructAs[A, T](c, lifetime); err != nil {
+		panic(err)
+	}
+}
@@ -117,6 +117,26 @@ func TestMustCall_It_Should_Panic_On_Error(t *testing.T) {
 	})
 }
 
+func TestMustCallWithResults_It_Should_Panic_On_Error(t *testing.T) {
+	c := container.New()
+
+	assert.Panics(t, func() {
+		container.MustCallWithResults(context.Background(), c, func(s Shape) int {
+			return s.GetArea()
+		})
+	})
+}
+
+func TestMustInvoke_It_Should_Panic_On_Error(t *testing.T) {
+	c := container.New()
+
+	assert.Panics(t, func() {
+		container.MustInvoke[int](context.Background(), c, func() (int, error) {
+			return 0, errors.New("failed")
+		})
+	})
+}
+
 func TestMustResolve_It_Should_Panic_On_Error(t *testing.T) {
 	c := container.New()
 	expectedErr := "failed making instance for type 'container_test.Shape'. Error: no binding found for abstraction 'container_test.Shape'"
@@ -0,0 +1,288 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Pooled lifetime means that the container reuses the concretes returned to the pool of the binding.
+// A concrete resolved with the Pooled lifetime is leased to the scope resolving it until that scope is closed or the
+// lease acquired with Acquire is released. It is then reset if it implements Resetter and returned to the pool.
+// Since the root container is never closed in practice, pooled bindings can only be resolved from it with Acquire.
+// Pooled bindings can only be dependencies of transient bindings, the concretes of other lifetimes outliving the lease.
+// Concretes returned to a full pool, and the pooled concretes left when the container the binding is registered in is
+// closed, are closed if they implement io.Closer.
+const Pooled Lifetime = "pooled"
+
+// DefaultPoolSize is the number of idle concretes kept by the pools of the Pooled lifetime.
+// It does not limit the number of leased concretes: a resolution finding the pool empty always constructs a concrete.
+const DefaultPoolSize = 16
+
+// Resetter is implemented by pooled concretes needing to be reset before being reused.
+type Resetter interface {
+	Reset()
+}
+
+// NewPooledLifetime returns the strategy of a pooled lifetime keeping at most size idle concretes per binding.
+// The size bounds the concretes waiting in the pool, not those leased. A binding registered with
+// RegisterOptions.PoolSize keeps that many idle concretes instead.
+func NewPooledLifetime(size int) LifetimeStrategy {
+	return &pooledLifetime{size: size}
+}
+
+type pooledLifetime struct {
+	size int
+}
+
+// dispose closes the idle concretes of the binding.
+func (l *pooledLifetime) dispose(b *binding) error {
+	b.mu.Lock()
+	p := b.pool
+	b.mu.Unlock()
+
+	if p == nil {
+		return nil
+	}
+
+	return p.drain()
+}
+
+func (l *pooledLifetime) Resolve(ctx context.Context, request LifetimeRequest) (interface{}, bool, error) {
+	b := request.binding
+
+	// The slot receives the lease when the binding is resolved by Acquire.
+	slot, _ := ctx.Value(leaseSlotKey{}).(*leaseSlot)
+	if slot != nil && (slot.binding != b || slot.lease != nil) {
+		slot = nil
+	}
+
+	// A concrete kept by a dependent outliving the lease would be reset and leased again while still in use.
+	for frame := resolvingFrames(ctx).parent; frame != nil; frame = frame.parent {
+		if frame.binding.lifetime != Transient {
+			return nil, false, fmt.Errorf("%w, the pooled '%s' cannot be a dependency of '%s' with the %s lifetime",
+				ErrInvalidLifetime, request.Type.String(), frame.t.String(), frame.binding.lifetime)
+		}
+	}
+
+	if request.Scope.parent == nil && slot == nil {
+		return nil, false, fmt.Errorf("%w, the pooled '%s' can only be resolved from the root container with Acquire",
+			ErrInvalidLifetime, request.Type.String())
+	}
+
+	b.mu.Lock()
+	if b.pool == nil {
+		size := l.size
+		if b.poolSize > 0 {
+			size = b.poolSize
+		}
+		b.pool = &pool{size: size}
+	}
+	p := b.pool
+	b.mu.Unlock()
+
+	concrete, reused := p.get()
+	if !reused {
+		var err error
+		if concrete, err = request.Construct(ctx); err != nil {
+			return concrete, false, err
+		}
+	}
+
+	leased := l.lease(request, p, concrete)
+	if slot != nil {
+		slot.lease = leased
+	}
+
+	return concrete, reused, nil
+}
+
+// lease records the concrete as leased by the container resolving it.
+func (l *pooledLifetime) lease(request LifetimeRequest, p *pool, concrete interface{}) *lease {
+	leased := &lease{pool: p, concrete: concrete, holder: request.Scope}
+
+	holder := request.Scope
+	holder.mu.Lock()
+	if holder.leases == nil {
+		holder.leases = map[*lease]struct{}{}
+	}
+	holder.leases[leased] = struct{}{}
+	holder.mu.Unlock()
+
+	return leased
+}
+
+// pool holds the idle concretes of a pooled binding.
+type pool struct {
+	mu   sync.Mutex
+	size int
+	idle []interface{}
+}
+
+// get returns an idle concrete, if any.
+func (p *pool) get() (interface{}, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.idle) == 0 {
+		return nil, false
+	}
+
+	concrete := p.idle[len(p.idle)-1]
+	p.idle = p.idle[:len(p.idle)-1]
+
+	return concrete, true
+}
+
+// put resets the concrete and returns it to the pool, or closes it if the pool is full.
+func (p *pool) put(concrete interface{}) error {
+	if resetter, ok := concrete.(Resetter); ok {
+		resetter.Reset()
+	}
+
+	p.mu.Lock()
+	if len(p.idle) < p.size {
+		p.idle = append(p.idle, concrete)
+		p.mu.Unlock()
+		return nil
+	}
+	p.mu.Unlock()
+
+	return closeConcrete(concrete)
+}
+
+// drain closes the idle concretes.
+func (p *pool) drain() error {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.mu.Unlock()
+
+	var errs []error
+	for _, concrete := range idle {
+		if err := closeConcrete(concrete); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func closeConcrete(concrete interface{}) error {
+	if closer, ok := concrete.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// lease is a pooled concrete held by the container that resolved it.
+type lease struct {
+	pool     *pool
+	concrete interface{}
+	holder   *Container
+	once     sync.Once
+}
+
+// release returns the concrete to its pool, once.
+func (l *lease) release() error {
+	var err error
+	l.once.Do(func() {
+		l.holder.mu.Lock()
+		delete(l.holder.leases, l)
+		l.holder.mu.Unlock()
+
+		err = l.pool.put(l.concrete)
+	})
+
+	return err
+}
+
+// releaseLeases returns the concretes leased by the container to their pools.
+func (c *Container) releaseLeases() error {
+	c.mu.Lock()
+	leases := make([]*lease, 0, len(c.leases))
+	for l := range c.leases {
+		leases = append(leases, l)
+	}
+	c.mu.Unlock()
+
+	var errs []error
+	for _, l := range leases {
+		if err := l.release(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+type leaseSlotKey struct{}
+
+// leaseSlot receives the lease of the binding resolved by Acquire.
+type leaseSlot struct {
+	binding *binding
+	lease   *lease
+}
+
+// Lease is a pooled concrete acquired with Acquire.
+type Lease[T any] struct {
+	Value T
+
+	lease *lease
+}
+
+// Release returns the concrete to its pool before the container it was acquired from is closed.
+// The concrete must not be used once released. Releasing a lease again does nothing.
+func (l *Lease[T]) Release() error {
+	return l.lease.release()
+}
+
+// Acquire resolves the binding of T registered with a pooled lifetime and returns it as a lease, so it can be returned
+// to the pool with Release before the container is closed.
+func Acquire[T any](ctx context.Context, c *Container) (*Lease[T], error) {
+	return AcquireNamed[T](ctx, c, "")
+}
+
+// AcquireNamed resolves the named binding of T registered with a pooled lifetime and returns it as a lease.
+func AcquireNamed[T any](ctx context.Context, c *Container, name string) (*Lease[T], error) {
+	if ctx == nil {
+		return nil, ErrContextRequired
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	b, _ := c.lookup(t, name)
+	if b == nil {
+		return nil, fmt.Errorf("%w for abstraction '%s'", ErrBindingNotFound, t.String())
+	}
+
+	if _, pooled := c.lifetimeStrategy(b.lifetime).(*pooledLifetime); !pooled {
+		return nil, fmt.Errorf("%w, the binding of '%s' is not pooled", ErrInvalidLifetime, t.String())
+	}
+
+	concrete, leased, err := c.acquire(ctx, t, name, b)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Lease[T]{lease: leased}
+	if concrete != nil {
+		result.Value = concrete.(T)
+	}
+
+	return result, nil
+}
+
+// acquire resolves the pooled binding and returns the concrete along with its lease.
+func (c *Container) acquire(ctx context.Context, t reflect.Type, name string, b *binding) (interface{}, *lease, error) {
+	slot := &leaseSlot{binding: b}
+	concrete, err := c.make(context.WithValue(ctx, leaseSlotKey{}, slot), t, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return concrete, slot.lease, nil
+}
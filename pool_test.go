@@ -0,0 +1,303 @@
+package container_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+type Encoder struct {
+	buffer bytes.Buffer
+	resets int
+	closed bool
+}
+
+func (e *Encoder) Reset() {
+	e.resets++
+	e.buffer.Reset()
+}
+
+func (e *Encoder) Close() error {
+	e.closed = true
+	return nil
+}
+
+type Document struct {
+	Encoder *Encoder
+}
+
+func TestContainer_Resolve_Pooled_Returned_On_Scope_Close(t *testing.T) {
+	constructions := 0
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() *Encoder {
+			constructions++
+			return &Encoder{}
+		},
+		Lifetime: container.Pooled,
+	})
+	assert.NoError(t, err)
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	var first, second *Encoder
+	assert.NoError(t, scope.Resolve(context.Background(), &first))
+	assert.NoError(t, scope.Resolve(context.Background(), &second))
+	assert.NotSame(t, first, second)
+	first.buffer.WriteString("data")
+
+	assert.NoError(t, scope.Close())
+	assert.False(t, first.closed)
+	assert.Equal(t, 1, first.resets)
+	assert.Equal(t, 0, first.buffer.Len())
+
+	other, err := c.NewScope()
+	assert.NoError(t, err)
+
+	var reused *Encoder
+	assert.NoError(t, other.Resolve(context.Background(), &reused))
+	assert.True(t, reused == first || reused == second)
+	assert.Equal(t, 2, constructions)
+}
+
+func TestContainer_Acquire_And_Release(t *testing.T) {
+	constructions := 0
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() *Encoder {
+			constructions++
+			return &Encoder{}
+		},
+		Lifetime: container.Pooled,
+	})
+	assert.NoError(t, err)
+
+	lease, err := container.Acquire[*Encoder](context.Background(), c)
+	assert.NoError(t, err)
+	assert.NotNil(t, lease.Value)
+
+	assert.NoError(t, lease.Release())
+	assert.NoError(t, lease.Release())
+	assert.Equal(t, 1, lease.Value.resets)
+
+	again, err := container.Acquire[*Encoder](context.Background(), c)
+	assert.NoError(t, err)
+	assert.Same(t, lease.Value, again.Value)
+	assert.Equal(t, 1, constructions)
+
+	// Closing the container releases the lease and closes the idle concretes.
+	assert.NoError(t, c.Close())
+	assert.Equal(t, 2, again.Value.resets)
+	assert.True(t, again.Value.closed)
+}
+
+func TestContainer_Acquire_Not_Pooled_It_Should_Fail(t *testing.T) {
+	c := container.New()
+	container.MustRegisterSingleton(c, func() *Encoder {
+		return &Encoder{}
+	})
+
+	_, err := container.Acquire[*Encoder](context.Background(), c)
+	assert.ErrorIs(t, err, container.ErrInvalidLifetime)
+
+	_, err = container.Acquire[Shape](context.Background(), c)
+	assert.ErrorIs(t, err, container.ErrBindingNotFound)
+}
+
+func TestContainer_Pooled_With_Maximum_Size(t *testing.T) {
+	c := container.New()
+	assert.NoError(t, c.RegisterLifetime("small-pool", container.NewPooledLifetime(1)))
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() *Encoder { return &Encoder{} },
+		Lifetime: "small-pool",
+	})
+	assert.NoError(t, err)
+
+	first, err := container.Acquire[*Encoder](context.Background(), c)
+	assert.NoError(t, err)
+	second, err := container.Acquire[*Encoder](context.Background(), c)
+	assert.NoError(t, err)
+
+	assert.NoError(t, first.Release())
+	assert.NoError(t, second.Release())
+	assert.False(t, first.Value.closed)
+	assert.True(t, second.Value.closed)
+}
+
+func TestContainer_Pooled_With_Pool_Size(t *testing.T) {
+	c := container.New()
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() *Encoder { return &Encoder{} },
+		Lifetime: container.Pooled,
+		PoolSize: 2,
+	})
+	assert.NoError(t, err)
+
+	leases := []*container.Lease[*Encoder]{}
+	for i := 0; i < 3; i++ {
+		lease, err := container.Acquire[*Encoder](context.Background(), c)
+		assert.NoError(t, err)
+		leases = append(leases, lease)
+	}
+
+	for _, lease := range leases {
+		assert.NoError(t, lease.Release())
+	}
+	assert.False(t, leases[0].Value.closed)
+	assert.False(t, leases[1].Value.closed)
+	assert.True(t, leases[2].Value.closed)
+}
+
+func TestContainer_Register_Pool_Size_Not_Pooled_It_Should_Fail(t *testing.T) {
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() *Encoder { return &Encoder{} },
+		Lifetime: container.Singleton,
+		PoolSize: 2,
+	})
+	assert.ErrorIs(t, err, container.ErrInvalidLifetime)
+
+	err = c.Register(container.RegisterOptions{
+		Resolver: func() *Encoder { return &Encoder{} },
+		Lifetime: container.Pooled,
+		PoolSize: -1,
+	})
+	assert.ErrorIs(t, err, container.ErrInvalidLifetime)
+}
+
+func TestContainer_Pooled_Metrics(t *testing.T) {
+	constructions := 0
+	metrics := container.NewMemoryMetrics()
+	c := container.New(container.WithMetrics(metrics))
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() *Encoder {
+			constructions++
+			return &Encoder{}
+		},
+		Lifetime: container.Pooled,
+	})
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		lease, err := container.Acquire[*Encoder](context.Background(), c)
+		assert.NoError(t, err)
+		assert.NoError(t, lease.Release())
+	}
+
+	snapshot := metrics.Snapshot()
+	assert.Equal(t, 1, constructions)
+	assert.Equal(t, 2, snapshot.Types["*"+testPackage+".Encoder"].CacheHits)
+	assert.Equal(t, 0, snapshot.OpenDisposables)
+}
+
+func TestContainer_Resolve_Pooled_From_Root_It_Should_Fail(t *testing.T) {
+	c := container.New()
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() *Encoder { return &Encoder{} },
+		Lifetime: container.Pooled,
+	})
+	assert.NoError(t, err)
+
+	var encoder *Encoder
+	assert.ErrorIs(t, c.Resolve(context.Background(), &encoder), container.ErrInvalidLifetime)
+
+	lease, err := container.Acquire[*Encoder](context.Background(), c)
+	assert.NoError(t, err)
+	assert.NotNil(t, lease.Value)
+	assert.NoError(t, lease.Release())
+}
+
+func TestContainer_Validate_Pooled(t *testing.T) {
+	constructions := 0
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() *Encoder {
+			constructions++
+			return &Encoder{}
+		},
+		Lifetime: container.Pooled,
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.Validate(context.Background()))
+	assert.NoError(t, c.Validate(context.Background()))
+	assert.Equal(t, 1, constructions)
+}
+
+func TestContainer_Resolve_Pooled_Dependency_Of_Transient(t *testing.T) {
+	c := container.New()
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() *Encoder { return &Encoder{} },
+		Lifetime: container.Pooled,
+	})
+	assert.NoError(t, err)
+	container.MustRegisterTransient(c, func(encoder *Encoder) *Document {
+		return &Document{Encoder: encoder}
+	})
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	var document *Document
+	assert.NoError(t, scope.Resolve(context.Background(), &document))
+	assert.NotNil(t, document.Encoder)
+}
+
+func TestContainer_Resolve_Pooled_Dependency_Of_Caching_Binding_It_Should_Fail(t *testing.T) {
+	for _, lifetime := range []container.Lifetime{container.Singleton, container.Scoped, "cached"} {
+		t.Run(string(lifetime), func(t *testing.T) {
+			c := container.New()
+			assert.NoError(t, c.RegisterLifetime("cached", container.NewExpiringLifetime(container.ExpiringOptions{})))
+
+			err := c.Register(container.RegisterOptions{
+				Resolver: func() *Encoder { return &Encoder{} },
+				Lifetime: container.Pooled,
+			})
+			assert.NoError(t, err)
+			err = c.Register(container.RegisterOptions{
+				Resolver: func(encoder *Encoder) *Document { return &Document{Encoder: encoder} },
+				Lifetime: lifetime,
+			})
+			assert.NoError(t, err)
+
+			scope, err := c.NewScope()
+			assert.NoError(t, err)
+
+			var document *Document
+			err = scope.Resolve(context.Background(), &document)
+			if assert.ErrorIs(t, err, container.ErrInvalidLifetime) {
+				assert.Contains(t, err.Error(), "cannot be a dependency of '*container_test.Document'")
+			}
+		})
+	}
+}
+
+func TestContainer_WarmUp_Pooled_Dependency_Of_Singleton_It_Should_Fail(t *testing.T) {
+	c := container.New()
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() *Encoder { return &Encoder{} },
+		Lifetime: container.Pooled,
+	})
+	assert.NoError(t, err)
+	err = c.Register(container.RegisterOptions{
+		Resolver: func(encoder *Encoder) *Document { return &Document{Encoder: encoder} },
+		Lifetime: container.Singleton,
+		Eager:    true,
+	})
+	assert.NoError(t, err)
+
+	err = c.WarmUp(context.Background())
+	if assert.ErrorIs(t, err, container.ErrInvalidLifetime) {
+		assert.Contains(t, err.Error(), "cannot be a dependency of '*container_test.Document'")
+	}
+}
@@ -0,0 +1,86 @@
+package container
+
+// ScopeOption configures a scope created with NewScope.
+type ScopeOption func(scope *Container) error
+
+// WithKind sets the kind of the scope, such as "request", "job" or "tenant".
+// Scoped bindings registered with RegisterOptions.ScopeKind are resolved from the nearest scope of their kind.
+func WithKind(kind string) ScopeOption {
+	return func(scope *Container) error {
+		scope.kind = kind
+		return nil
+	}
+}
+
+// WithInstance registers the instance in the scope before it is returned, see RegisterInstance.
+func WithInstance(instance interface{}) ScopeOption {
+	return WithNamedInstance("", instance)
+}
+
+// WithNamedInstance registers the instance with a name in the scope before it is returned, see RegisterNamedInstance.
+func WithNamedInstance(name string, instance interface{}) ScopeOption {
+	return func(scope *Container) error {
+		return scope.RegisterNamedInstance(name, instance)
+	}
+}
+
+// WithInstanceAs registers the instance as T in the scope before it is returned, see RegisterInstanceAs.
+func WithInstanceAs[T any](instance T) ScopeOption {
+	return WithNamedInstanceAs("", instance)
+}
+
+// WithNamedInstanceAs registers the instance as T with a name in the scope before it is returned, see
+// RegisterNamedInstanceAs.
+func WithNamedInstanceAs[T any](name string, instance T) ScopeOption {
+	return func(scope *Container) error {
+		return RegisterNamedInstanceAs(scope, name, instance)
+	}
+}
+
+// WithRegistration registers the resolver in the scope before it is returned, see Register.
+//
+//	scope, err := c.NewScope(
+//		container.WithInstanceAs[*User](user),
+//		container.WithRegistration(container.RegisterOptions{Resolver: newAuditLog, Lifetime: container.Scoped}),
+//	)
+func WithRegistration(options RegisterOptions) ScopeOption {
+	return func(scope *Container) error {
+		return scope.Register(options)
+	}
+}
+
+// Kind returns the kind the scope was created with, or an empty string for the root container and anonymous scopes.
+func (c *Container) Kind() string {
+	return c.kind
+}
+
+// scopeOfKind returns the container or its nearest ancestor of the kind, or nil if there is none.
+func (c *Container) scopeOfKind(kind string) *Container {
+	for current := c; current != nil; current = current.parent {
+		if current.kind == kind {
+			return current
+		}
+	}
+
+	return nil
+}
+
+// scopedBinding returns the binding of the scope for a scoped binding registered in one of its parents, creating it
+// on first use. Every scope thus resolves the scoped bindings once, whether they were registered before or after the
+// scope was created.
+func (c *Container) scopedBinding(registered *binding) *binding {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.scoped == nil {
+		c.scoped = map[*binding]*binding{}
+	}
+
+	scoped, exist := c.scoped[registered]
+	if !exist {
+		scoped = registered.clone()
+		c.scoped[registered] = scoped
+	}
+
+	return scoped
+}
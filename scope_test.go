@@ -0,0 +1,246 @@
+package container_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+func TestContainer_NewScope_WithKind(t *testing.T) {
+	c := container.New()
+	assert.Empty(t, c.Kind())
+
+	scope, err := c.NewScope(container.WithKind("request"))
+	assert.NoError(t, err)
+	assert.Equal(t, "request", scope.Kind())
+
+	anonymous, err := scope.NewScope()
+	assert.NoError(t, err)
+	assert.Empty(t, anonymous.Kind())
+}
+
+func TestContainer_Resolve_Scope_Kind(t *testing.T) {
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() Database {
+			return &MySQL{options: &DatabaseOptions{}}
+		},
+		Lifetime:  container.Scoped,
+		ScopeKind: "tenant",
+	})
+	assert.NoError(t, err)
+
+	tenant, err := c.NewScope(container.WithKind("tenant"))
+	assert.NoError(t, err)
+	request, err := tenant.NewScope(container.WithKind("request"))
+	assert.NoError(t, err)
+
+	var fromTenant, fromRequest Database
+	assert.NoError(t, tenant.Resolve(context.Background(), &fromTenant))
+	assert.NoError(t, request.Resolve(context.Background(), &fromRequest))
+	assert.Same(t, fromTenant, fromRequest)
+
+	other, err := c.NewScope(container.WithKind("tenant"))
+	assert.NoError(t, err)
+
+	var fromOther Database
+	assert.NoError(t, other.Resolve(context.Background(), &fromOther))
+	assert.NotSame(t, fromTenant, fromOther)
+}
+
+func TestContainer_Resolve_Scope_Kind_Mismatch(t *testing.T) {
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() Database {
+			return &MySQL{options: &DatabaseOptions{}}
+		},
+		Lifetime:  container.Scoped,
+		ScopeKind: "tenant",
+	})
+	assert.NoError(t, err)
+
+	job, err := c.NewScope(container.WithKind("job"))
+	assert.NoError(t, err)
+
+	var instance Database
+	assert.ErrorIs(t, c.Resolve(context.Background(), &instance), container.ErrScopeMismatch)
+	assert.ErrorIs(t, job.Resolve(context.Background(), &instance), container.ErrScopeMismatch)
+}
+
+func TestContainer_Validate_Scope_Kind(t *testing.T) {
+	constructions := 0
+	registration := container.RegisterOptions{
+		Resolver: func() Database {
+			constructions++
+			return &MySQL{options: &DatabaseOptions{}}
+		},
+		Lifetime:  container.Scoped,
+		ScopeKind: "tenant",
+	}
+
+	c := container.New()
+	assert.NoError(t, c.Register(registration))
+	assert.NoError(t, c.Validate(context.Background()))
+
+	anonymous, err := c.NewScope(container.WithRegistration(registration))
+	assert.NoError(t, err)
+	assert.NoError(t, anonymous.Validate(context.Background()))
+	assert.Equal(t, 0, constructions)
+
+	tenant, err := c.NewScope(container.WithKind("tenant"), container.WithRegistration(registration))
+	assert.NoError(t, err)
+	assert.NoError(t, tenant.Validate(context.Background()))
+	assert.Equal(t, 1, constructions)
+}
+
+func TestContainer_Register_Scope_Kind_Requires_Scoped(t *testing.T) {
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() Database {
+			return &MySQL{}
+		},
+		Lifetime:  container.Singleton,
+		ScopeKind: "tenant",
+	})
+	assert.ErrorIs(t, err, container.ErrInvalidLifetime)
+}
+
+func TestContainer_NewScope_Option_Error(t *testing.T) {
+	c := container.New()
+
+	failing := func(scope *container.Container) error {
+		return container.ErrInvalidReceiver
+	}
+
+	scope, err := c.NewScope(failing)
+	assert.ErrorIs(t, err, container.ErrInvalidReceiver)
+	assert.Nil(t, scope)
+}
+
+func TestContainer_Resolve_Scoped_Registered_After_Scope(t *testing.T) {
+	c := container.New()
+
+	first, err := c.NewScope()
+	assert.NoError(t, err)
+	second, err := c.NewScope()
+	assert.NoError(t, err)
+
+	container.MustRegisterScoped(c, func() Database {
+		return &MySQL{options: &DatabaseOptions{}}
+	})
+
+	var fromFirst, fromFirstAgain, fromSecond, fromRoot Database
+	assert.NoError(t, first.Resolve(context.Background(), &fromFirst))
+	assert.NoError(t, first.Resolve(context.Background(), &fromFirstAgain))
+	assert.NoError(t, second.Resolve(context.Background(), &fromSecond))
+	assert.NoError(t, c.Resolve(context.Background(), &fromRoot))
+
+	assert.Same(t, fromFirst, fromFirstAgain)
+	assert.NotSame(t, fromFirst, fromSecond)
+	assert.NotSame(t, fromFirst, fromRoot)
+	assert.NotSame(t, fromSecond, fromRoot)
+}
+
+func TestContainer_Resolve_Scoped_Nested_Scopes(t *testing.T) {
+	c := container.New()
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+	nested, err := scope.NewScope()
+	assert.NoError(t, err)
+
+	container.MustRegisterScoped(c, func() Database {
+		return &MySQL{options: &DatabaseOptions{}}
+	})
+	container.MustRegisterScoped(scope, func() Shape {
+		return &Circle{}
+	})
+
+	var database, nestedDatabase Database
+	assert.NoError(t, scope.Resolve(context.Background(), &database))
+	assert.NoError(t, nested.Resolve(context.Background(), &nestedDatabase))
+	assert.NotSame(t, database, nestedDatabase)
+
+	var shape, nestedShape Shape
+	assert.NoError(t, scope.Resolve(context.Background(), &shape))
+	assert.NoError(t, nested.Resolve(context.Background(), &nestedShape))
+	assert.NotSame(t, shape, nestedShape)
+
+	var shapeAgain Shape
+	assert.NoError(t, nested.Resolve(context.Background(), &shapeAgain))
+	assert.Same(t, nestedShape, shapeAgain)
+}
+
+func TestContainer_Resolve_Scoped_Registered_Again(t *testing.T) {
+	c := container.New()
+	container.MustRegisterScoped(c, func() Shape {
+		return &Circle{}
+	})
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	var circle Shape
+	assert.NoError(t, scope.Resolve(context.Background(), &circle))
+	assert.IsType(t, &Circle{}, circle)
+
+	container.MustRegisterScoped(c, func() Shape {
+		return &Square{}
+	})
+
+	var square Shape
+	assert.NoError(t, scope.Resolve(context.Background(), &square))
+	assert.IsType(t, &Square{}, square)
+}
+
+type User struct {
+	Name string
+}
+
+func TestContainer_NewScope_With_Registrations(t *testing.T) {
+	c := container.New()
+
+	user := &User{Name: "alice"}
+	scope, err := c.NewScope(
+		container.WithInstance(user),
+		container.WithNamedInstanceAs[Shape]("current", &Circle{a: 1}),
+		container.WithRegistration(container.RegisterOptions{
+			Resolver: func(u *User) Database {
+				return &MySQL{options: &DatabaseOptions{Username: u.Name}}
+			},
+			Lifetime: container.Scoped,
+		}),
+	)
+	assert.NoError(t, err)
+
+	var resolvedUser *User
+	assert.NoError(t, scope.Resolve(context.Background(), &resolvedUser))
+	assert.Same(t, user, resolvedUser)
+
+	var shape Shape
+	assert.NoError(t, scope.ResolveNamed(context.Background(), "current", &shape))
+	assert.Equal(t, 1, shape.GetArea())
+
+	var database Database
+	assert.NoError(t, scope.Resolve(context.Background(), &database))
+	assert.Equal(t, "alice", database.(*MySQL).options.Username)
+
+	assert.ErrorIs(t, c.Resolve(context.Background(), &resolvedUser), container.ErrBindingNotFound)
+}
+
+func TestContainer_NewScope_With_Invalid_Registration(t *testing.T) {
+	c := container.New()
+
+	scope, err := c.NewScope(
+		container.WithInstance(&User{}),
+		container.WithRegistration(container.RegisterOptions{Resolver: func() {}}),
+	)
+	assert.ErrorIs(t, err, container.ErrInvalidResolver)
+	assert.Nil(t, scope)
+	assert.Equal(t, 0, c.ActiveScopes())
+}
@@ -0,0 +1,202 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceFrame is a single resolution recorded by a Tracer.
+// Resolutions made while constructing the concrete of a frame are recorded as its children.
+type TraceFrame struct {
+	Type     string
+	Name     string
+	Lifetime Lifetime
+	// Cached is true when the concrete was returned from the cache instead of being constructed.
+	Cached   bool
+	Duration time.Duration
+	Err      error
+	Children []*TraceFrame
+
+	parent *TraceFrame
+	start  time.Time
+}
+
+// Parent returns the frame whose construction caused this resolution, or nil for a top level resolution.
+func (f *TraceFrame) Parent() *TraceFrame {
+	return f.parent
+}
+
+// label returns the name of the frame used in reports.
+func (f *TraceFrame) label() string {
+	if f.Name == "" {
+		return f.Type
+	}
+
+	return f.Type + "#" + f.Name
+}
+
+// Tracer records every resolution made by the containers it is attached to with WithTracer.
+// It is safe to use from multiple goroutines.
+type Tracer struct {
+	mu     sync.Mutex
+	frames []*TraceFrame
+}
+
+// NewTracer creates a new empty Tracer.
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+// WithTracer attaches the tracer to the container and the scopes created from it.
+func WithTracer(tracer *Tracer) Option {
+	return func(c *Container) {
+		c.tracer = tracer
+	}
+}
+
+type traceFrameKey struct{}
+
+// begin records the start of a resolution as a child of the frame carried by the context, if any.
+// The returned context carries the new frame so nested resolutions are recorded as its children.
+func (t *Tracer) begin(ctx context.Context, abstraction reflect.Type, name string, lifetime Lifetime) (context.Context, *TraceFrame) {
+	parent, _ := ctx.Value(traceFrameKey{}).(*TraceFrame)
+	frame := &TraceFrame{
+		Type:     abstraction.String(),
+		Name:     name,
+		Lifetime: lifetime,
+		parent:   parent,
+		start:    time.Now(),
+	}
+
+	t.mu.Lock()
+	if parent == nil {
+		t.frames = append(t.frames, frame)
+	} else {
+		parent.Children = append(parent.Children, frame)
+	}
+	t.mu.Unlock()
+
+	return context.WithValue(ctx, traceFrameKey{}, frame), frame
+}
+
+// end records the outcome of a resolution started with begin.
+func (t *Tracer) end(frame *TraceFrame, cached bool, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	frame.Duration = time.Since(frame.start)
+	frame.Cached = cached
+	frame.Err = err
+}
+
+// Frames returns the top level resolutions recorded so far.
+func (t *Tracer) Frames() []*TraceFrame {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]*TraceFrame{}, t.frames...)
+}
+
+// Reset discards the recorded resolutions.
+func (t *Tracer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.frames = nil
+}
+
+// WriteReport writes the recorded resolutions as a tree, one resolution per line.
+func (t *Tracer) WriteReport(w io.Writer) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var sb strings.Builder
+	var walk func(frames []*TraceFrame, prefix string)
+
+	walk = func(frames []*TraceFrame, prefix string) {
+		for i, frame := range frames {
+			connector, indent := "├── ", "│   "
+			if i == len(frames)-1 {
+				connector, indent = "└── ", "    "
+			}
+
+			fmt.Fprintf(&sb, "%s%s%s\n", prefix, connector, describeFrame(frame))
+			walk(frame.Children, prefix+indent)
+		}
+	}
+
+	for _, frame := range t.frames {
+		fmt.Fprintln(&sb, describeFrame(frame))
+		walk(frame.Children, "")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteFolded writes the recorded resolutions in the folded stacks format understood by flame graph tools.
+// Each line holds a stack of resolutions separated by semicolons followed by the time spent in the last resolution
+// itself, excluding its children, in microseconds. Identical stacks are merged.
+func (t *Tracer) WriteFolded(w io.Writer) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	totals := map[string]int64{}
+	var walk func(frame *TraceFrame, stack string)
+
+	walk = func(frame *TraceFrame, stack string) {
+		if stack != "" {
+			stack += ";"
+		}
+		stack += strings.ReplaceAll(frame.label(), ";", ":")
+
+		self := frame.Duration
+		for _, child := range frame.Children {
+			self -= child.Duration
+			walk(child, stack)
+		}
+
+		if self < 0 {
+			self = 0
+		}
+
+		totals[stack] += self.Microseconds()
+	}
+
+	for _, frame := range t.frames {
+		walk(frame, "")
+	}
+
+	stacks := make([]string, 0, len(totals))
+	for stack := range totals {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+
+	var sb strings.Builder
+	for _, stack := range stacks {
+		fmt.Fprintf(&sb, "%s %d\n", stack, totals[stack])
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// describeFrame returns a one line summary of a frame.
+func describeFrame(frame *TraceFrame) string {
+	outcome := "constructed"
+	if frame.Cached {
+		outcome = "cached"
+	}
+	if frame.Err != nil {
+		outcome = "failed"
+	}
+
+	return fmt.Sprintf("%s [%s] %s in %s", frame.label(), frame.Lifetime, outcome, frame.Duration)
+}
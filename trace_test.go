@@ -0,0 +1,186 @@
+package container_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+func TestTracer_Records_Resolution_Tree(t *testing.T) {
+	tracer := container.NewTracer()
+	c := container.New(container.WithTracer(tracer))
+
+	err := c.RegisterSingleton(func() *DatabaseOptions {
+		return &DatabaseOptions{}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterTransient(func(options *DatabaseOptions) Database {
+		return &MySQL{options: options}
+	})
+	assert.NoError(t, err)
+
+	var db Database
+	err = c.Resolve(context.Background(), &db)
+	assert.NoError(t, err)
+	err = c.Resolve(context.Background(), &db)
+	assert.NoError(t, err)
+
+	frames := tracer.Frames()
+	assert.Len(t, frames, 2)
+
+	for i, frame := range frames {
+		assert.Equal(t, "container_test.Database", frame.Type)
+		assert.Equal(t, container.Transient, frame.Lifetime)
+		assert.False(t, frame.Cached)
+		assert.Nil(t, frame.Parent())
+		assert.Len(t, frame.Children, 1)
+
+		child := frame.Children[0]
+		assert.Equal(t, "*container_test.DatabaseOptions", child.Type)
+		assert.Equal(t, container.Singleton, child.Lifetime)
+		assert.Same(t, frame, child.Parent())
+		assert.Equal(t, i == 1, child.Cached)
+		assert.LessOrEqual(t, child.Duration, frame.Duration)
+	}
+}
+
+func TestTracer_Records_Nested_Resolve_Calls(t *testing.T) {
+	tracer := container.NewTracer()
+	c := container.New(container.WithTracer(tracer))
+
+	container.MustRegisterSingleton(c, func() *DatabaseOptions {
+		return &DatabaseOptions{}
+	})
+
+	container.MustRegisterSingleton(c, func(ctx context.Context) (Database, error) {
+		var options *DatabaseOptions
+		if err := c.Resolve(ctx, &options); err != nil {
+			return nil, err
+		}
+		return &MySQL{options: options}, nil
+	})
+
+	var db Database
+	err := c.Resolve(context.Background(), &db)
+	assert.NoError(t, err)
+
+	frames := tracer.Frames()
+	assert.Len(t, frames, 1)
+	assert.Len(t, frames[0].Children, 1)
+	assert.Equal(t, "*container_test.DatabaseOptions", frames[0].Children[0].Type)
+}
+
+func TestTracer_Records_Failures(t *testing.T) {
+	tracer := container.NewTracer()
+	c := container.New(container.WithTracer(tracer))
+
+	resolveErr := errors.New("app: cannot connect")
+	container.MustRegisterNamedSingleton(c, "primary", func() (Database, error) {
+		return nil, resolveErr
+	})
+
+	var db Database
+	err := c.ResolveNamed(context.Background(), "primary", &db)
+	assert.Error(t, err)
+
+	frames := tracer.Frames()
+	assert.Len(t, frames, 1)
+	assert.Equal(t, "primary", frames[0].Name)
+	assert.ErrorIs(t, frames[0].Err, resolveErr)
+}
+
+func TestTracer_Is_Inherited_By_Scopes(t *testing.T) {
+	tracer := container.NewTracer()
+	c := container.New(container.WithTracer(tracer))
+
+	err := c.RegisterSingleton(func() *DatabaseOptions {
+		return &DatabaseOptions{}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterTransient(func(options *DatabaseOptions) Database {
+		return &MySQL{options: options}
+	})
+	assert.NoError(t, err)
+
+	scope, err := c.NewScope()
+	assert.NoError(t, err)
+
+	var db Database
+	err = scope.Resolve(context.Background(), &db)
+	assert.NoError(t, err)
+
+	assert.Len(t, tracer.Frames(), 1)
+
+	tracer.Reset()
+	assert.Len(t, tracer.Frames(), 0)
+}
+
+func TestTracer_WriteReport(t *testing.T) {
+	tracer := container.NewTracer()
+	c := container.New(container.WithTracer(tracer))
+
+	err := c.RegisterSingleton(func() *DatabaseOptions {
+		return &DatabaseOptions{}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterTransient(func(options *DatabaseOptions) Database {
+		return &MySQL{options: options}
+	})
+	assert.NoError(t, err)
+
+	var db Database
+	err = c.Resolve(context.Background(), &db)
+	assert.NoError(t, err)
+	err = c.Resolve(context.Background(), &db)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = tracer.WriteReport(&buf)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 4)
+	assert.True(t, strings.HasPrefix(lines[0], "container_test.Database [transient] constructed in "))
+	assert.True(t, strings.HasPrefix(lines[1], "└── *container_test.DatabaseOptions [singleton] constructed in "))
+	assert.True(t, strings.HasPrefix(lines[2], "container_test.Database [transient] constructed in "))
+	assert.True(t, strings.HasPrefix(lines[3], "└── *container_test.DatabaseOptions [singleton] cached in "))
+}
+
+func TestTracer_WriteFolded(t *testing.T) {
+	tracer := container.NewTracer()
+	c := container.New(container.WithTracer(tracer))
+
+	err := c.RegisterSingleton(func() *DatabaseOptions {
+		return &DatabaseOptions{}
+	})
+	assert.NoError(t, err)
+
+	err = c.RegisterTransient(func(options *DatabaseOptions) Database {
+		return &MySQL{options: options}
+	})
+	assert.NoError(t, err)
+
+	var db Database
+	err = c.Resolve(context.Background(), &db)
+	assert.NoError(t, err)
+	err = c.Resolve(context.Background(), &db)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = tracer.WriteFolded(&buf)
+	assert.NoError(t, err)
+
+	// Identical stacks are merged into a single line.
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	assert.Regexp(t, `^container_test\.Database \d+$`, lines[0])
+	assert.Regexp(t, `^container_test\.Database;\*container_test\.DatabaseOptions \d+$`, lines[1])
+}
@@ -0,0 +1,154 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+)
+
+// WithWarmUpParallelism sets the maximum number of singletons WarmUp constructs concurrently, for the container and
+// the scopes created from it. It defaults to runtime.GOMAXPROCS.
+func WithWarmUpParallelism(parallelism int) Option {
+	return func(c *Container) {
+		c.warmUpParallelism = parallelism
+	}
+}
+
+// WarmUp constructs the singletons registered with RegisterOptions.Eager visible from the container, along with the
+// singletons they depend on, so the first resolutions do not pay for their construction.
+// A singleton is constructed once the singletons it depends on are, independent singletons being constructed
+// concurrently up to the parallelism set with WithWarmUpParallelism.
+// Every failure is returned, joined with errors.Join. WarmUp stops starting constructions once the context is done.
+func (c *Container) WarmUp(ctx context.Context) error {
+	if ctx == nil {
+		return ErrContextRequired
+	}
+
+	visible := map[string]visibleBinding{}
+	for _, v := range c.visibleBindings() {
+		visible[graphNodeID(v.t, v.name)] = v
+	}
+
+	dependencies := map[string]map[string]bool{}
+	for _, edge := range c.Graph().Edges {
+		if dependencies[edge.From] == nil {
+			dependencies[edge.From] = map[string]bool{}
+		}
+		dependencies[edge.From][edge.To] = true
+	}
+
+	scheduled := map[string]bool{}
+	var schedule func(id string)
+	schedule = func(id string) {
+		v, exist := visible[id]
+		if scheduled[id] || !exist || v.binding.resolver == nil || v.binding.lifetime != Singleton {
+			return
+		}
+
+		scheduled[id] = true
+		for dependency := range dependencies[id] {
+			schedule(dependency)
+		}
+	}
+
+	for id, v := range visible {
+		if v.binding.eager {
+			schedule(id)
+		}
+	}
+
+	pending := map[string]int{}
+	dependents := map[string][]string{}
+	for id := range scheduled {
+		for dependency := range dependencies[id] {
+			if scheduled[dependency] && dependency != id {
+				pending[id]++
+				dependents[dependency] = append(dependents[dependency], id)
+			}
+		}
+	}
+
+	ready := []string{}
+	for id := range scheduled {
+		if pending[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+	sort.Strings(ready)
+
+	parallelism := c.warmUpParallelism
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+
+	type result struct {
+		id  string
+		err error
+	}
+
+	results := make(chan result)
+	construct := func(id string) {
+		v := visible[id]
+		if _, err := c.make(ctx, v.t, v.name); err != nil {
+			results <- result{id: id, err: fmt.Errorf("%w for type '%s', Error: %w", ErrResolutionFailed, id, err)}
+			return
+		}
+
+		results <- result{id: id}
+	}
+
+	var errs []error
+	running, done := 0, 0
+
+	for len(ready) > 0 || running > 0 {
+		for len(ready) > 0 && running < parallelism && ctx.Err() == nil {
+			running++
+			go construct(ready[0])
+			ready = ready[1:]
+		}
+
+		if running == 0 {
+			break
+		}
+
+		r := <-results
+		running--
+		done++
+
+		if r.err != nil {
+			errs = append(errs, r.err)
+		}
+
+		for _, dependent := range dependents[r.id] {
+			if pending[dependent]--; pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+
+	// The singletons left depend on each other, constructing them reports the circular dependency.
+	if done < len(scheduled) {
+		left := []string{}
+		for id := range scheduled {
+			if pending[id] > 0 {
+				left = append(left, id)
+			}
+		}
+		sort.Strings(left)
+
+		for _, id := range left {
+			v := visible[id]
+			if _, err := c.make(ctx, v.t, v.name); err != nil {
+				errs = append(errs, fmt.Errorf("%w for type '%s', Error: %w", ErrResolutionFailed, id, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,284 @@
+package container_test
+
+import (
+	"context"
+	"errors"
+	htmltemplate "html/template"
+	"sync"
+	"sync/atomic"
+	"testing"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wbreza/container/v4"
+)
+
+type Clock struct{}
+
+type Scheduler struct {
+	Clock *Clock
+}
+
+func TestContainer_WarmUp(t *testing.T) {
+	c := container.New()
+	order := []string{}
+	mu := sync.Mutex{}
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+	}
+
+	container.MustRegisterSingleton(c, func() *Clock {
+		record("clock")
+		return &Clock{}
+	})
+	container.MustRegisterSingleton(c, func() Shape {
+		record("shape")
+		return &Circle{}
+	})
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func(clock *Clock) *Scheduler {
+			record("scheduler")
+			return &Scheduler{Clock: clock}
+		},
+		Eager: true,
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.WarmUp(context.Background()))
+	assert.Equal(t, []string{"clock", "scheduler"}, order)
+
+	var scheduler *Scheduler
+	assert.NoError(t, c.Resolve(context.Background(), &scheduler))
+	assert.Equal(t, []string{"clock", "scheduler"}, order)
+
+	for _, registration := range c.Registrations() {
+		assert.Equal(t, registration.Type == "*container_test.Scheduler", registration.Eager)
+	}
+}
+
+func TestContainer_WarmUp_Constructs_Independent_Singletons_Concurrently(t *testing.T) {
+	c := container.New(container.WithWarmUpParallelism(2))
+	started := sync.WaitGroup{}
+	started.Add(2)
+
+	// Each resolver waits for the other one to start, which only happens when they run concurrently.
+	waitForBoth := func() error {
+		started.Done()
+
+		done := make(chan struct{})
+		go func() {
+			started.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			return nil
+		case <-time.After(5 * time.Second):
+			return errors.New("not constructed concurrently")
+		}
+	}
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() (*Clock, error) { return &Clock{}, waitForBoth() },
+		Eager:    true,
+	})
+	assert.NoError(t, err)
+	err = c.Register(container.RegisterOptions{
+		Resolver: func() (Shape, error) { return &Circle{}, waitForBoth() },
+		Eager:    true,
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.WarmUp(context.Background()))
+}
+
+func TestContainer_WarmUp_With_Parallelism(t *testing.T) {
+	c := container.New(container.WithWarmUpParallelism(1))
+	running, maximum := atomic.Int32{}, atomic.Int32{}
+
+	construct := func() {
+		current := running.Add(1)
+		if current > maximum.Load() {
+			maximum.Store(current)
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+	}
+
+	for _, name := range []string{"a", "b", "c", "d"} {
+		err := c.Register(container.RegisterOptions{
+			Resolver: func() Shape { construct(); return &Circle{} },
+			Name:     name,
+			Eager:    true,
+		})
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, c.WarmUp(context.Background()))
+	assert.Equal(t, int32(1), maximum.Load())
+}
+
+func TestContainer_WarmUp_Returns_All_Failures(t *testing.T) {
+	c := container.New()
+	first, second := errors.New("first"), errors.New("second")
+
+	for name, err := range map[string]error{"first": first, "second": second} {
+		err := err
+		assert.NoError(t, c.Register(container.RegisterOptions{
+			Resolver: func() (Shape, error) { return nil, err },
+			Name:     name,
+			Eager:    true,
+		}))
+	}
+
+	err := c.WarmUp(context.Background())
+	assert.ErrorIs(t, err, container.ErrResolutionFailed)
+	assert.ErrorIs(t, err, first)
+	assert.ErrorIs(t, err, second)
+}
+
+func TestContainer_WarmUp_With_Done_Context(t *testing.T) {
+	c := container.New()
+	constructed := false
+
+	assert.NoError(t, c.Register(container.RegisterOptions{
+		Resolver: func() Shape { constructed = true; return &Circle{} },
+		Eager:    true,
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, c.WarmUp(ctx), context.Canceled)
+	assert.False(t, constructed)
+}
+
+func TestContainer_Register_Eager_Not_Singleton_It_Should_Fail(t *testing.T) {
+	c := container.New()
+
+	err := c.Register(container.RegisterOptions{
+		Resolver: func() Shape { return &Circle{} },
+		Lifetime: container.Transient,
+		Eager:    true,
+	})
+	assert.ErrorIs(t, err, container.ErrInvalidLifetime)
+}
+
+func TestContainer_Resolve_Singleton_Concurrently_Constructs_Once(t *testing.T) {
+	c := container.New()
+	constructions := atomic.Int32{}
+
+	container.MustRegisterSingleton(c, func() Shape {
+		constructions.Add(1)
+		time.Sleep(time.Millisecond)
+		return &Circle{}
+	})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var s Shape
+			assert.NoError(t, c.Resolve(context.Background(), &s))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), constructions.Load())
+}
+
+func TestContainer_Resolve_With_Circular_Dependency_It_Should_Fail(t *testing.T) {
+	c := container.New()
+	container.MustRegisterSingleton(c, func(clock *Clock) *Scheduler {
+		return &Scheduler{Clock: clock}
+	})
+	container.MustRegisterSingleton(c, func(scheduler *Scheduler) *Clock {
+		return &Clock{}
+	})
+
+	var scheduler *Scheduler
+	err := c.Resolve(context.Background(), &scheduler)
+	assert.ErrorIs(t, err, container.ErrCircularDependency)
+
+	assert.NoError(t, c.Register(container.RegisterOptions{
+		Resolver: func(clock *Clock) *Scheduler { return &Scheduler{Clock: clock} },
+		Eager:    true,
+	}))
+	assert.ErrorIs(t, c.WarmUp(context.Background()), container.ErrCircularDependency)
+}
+
+func TestContainer_Resolve_With_Circular_Dependency_Through_Container_It_Should_Fail(t *testing.T) {
+	c := container.New()
+	container.MustRegisterSingleton(c, func(ctx context.Context, scope *container.Container) (*Scheduler, error) {
+		var clock *Clock
+		if err := scope.Resolve(ctx, &clock); err != nil {
+			return nil, err
+		}
+
+		return &Scheduler{Clock: clock}, nil
+	})
+	container.MustRegisterSingleton(c, func(scheduler *Scheduler) *Clock {
+		return &Clock{}
+	})
+
+	var scheduler *Scheduler
+	assert.ErrorIs(t, c.Resolve(context.Background(), &scheduler), container.ErrCircularDependency)
+
+	var clock *Clock
+	assert.ErrorIs(t, c.Resolve(context.Background(), &clock), container.ErrCircularDependency)
+}
+
+func TestContainer_WarmUp_Types_With_The_Same_Name_In_Different_Packages(t *testing.T) {
+	c := container.New()
+	constructed := []string{}
+
+	assert.NoError(t, c.Register(container.RegisterOptions{
+		Resolver: func() *texttemplate.Template {
+			constructed = append(constructed, "text")
+			return texttemplate.New("text")
+		},
+		Eager: true,
+	}))
+	assert.NoError(t, c.Register(container.RegisterOptions{
+		Resolver: func() *htmltemplate.Template {
+			constructed = append(constructed, "html")
+			return htmltemplate.New("html")
+		},
+		Eager: true,
+	}))
+
+	assert.NoError(t, c.WarmUp(context.Background()))
+	assert.ElementsMatch(t, []string{"text", "html"}, constructed)
+}
+
+func TestContainer_Resolve_With_The_Context_Of_A_Resolver(t *testing.T) {
+	type Worker struct {
+		ctx context.Context
+	}
+
+	c := container.New()
+	container.MustRegisterSingleton(c, func(ctx context.Context) *Worker {
+		return &Worker{ctx: ctx}
+	})
+	container.MustRegisterSingleton(c, func(worker *Worker) *Scheduler {
+		return &Scheduler{}
+	})
+
+	var scheduler *Scheduler
+	assert.NoError(t, c.Resolve(context.Background(), &scheduler))
+
+	var worker *Worker
+	assert.NoError(t, c.Resolve(context.Background(), &worker))
+
+	var again *Worker
+	assert.NoError(t, c.Resolve(worker.ctx, &again))
+	assert.Same(t, worker, again)
+	assert.NoError(t, c.Resolve(worker.ctx, &scheduler))
+}